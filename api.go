@@ -0,0 +1,117 @@
+package cs2gsi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// StateHandler returns the read-only REST API over the current game state:
+//
+//	GET /state                     full snapshot
+//	GET /state/players             players ordered by observer slot
+//	GET /state/players/{steamid}   a single player
+//	GET /state/rounds              round history
+//	GET /state/bomb                bomb state
+//	GET /state/grenades            active grenades keyed by id
+//	GET /state/damage              per-round damage used for ADR
+//	GET /state/schema              JSON Schema of the responses
+//
+// Every response is JSON built from Snapshot and carries
+// models.SchemaVersion in the X-Schema-Version header. Until the first game
+// state has been received the endpoints answer 503. Listen mounts it next to
+// the ingest endpoint; wrap it in http.StripPrefix to serve it under a
+// prefix.
+func (gsi *CS2GSI) StateHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /state", gsi.withSnapshot(func(state *models.State) (interface{}, bool) {
+		return state, true
+	}))
+	mux.HandleFunc("GET /state/players", gsi.withSnapshot(func(state *models.State) (interface{}, bool) {
+		return sortedPlayers(state.AllPlayers), true
+	}))
+	mux.HandleFunc("GET /state/players/{steamid}", func(w http.ResponseWriter, r *http.Request) {
+		steamID := r.PathValue("steamid")
+		gsi.withSnapshot(func(state *models.State) (interface{}, bool) {
+			player, ok := state.AllPlayers[steamID]
+			return player, ok
+		})(w, r)
+	})
+	mux.HandleFunc("GET /state/rounds", gsi.withSnapshot(func(state *models.State) (interface{}, bool) {
+		if state.Map == nil || state.Map.Rounds == nil {
+			return []models.RoundInfo{}, true
+		}
+		return state.Map.Rounds, true
+	}))
+	mux.HandleFunc("GET /state/bomb", gsi.withSnapshot(func(state *models.State) (interface{}, bool) {
+		return state.Bomb, state.Bomb != nil
+	}))
+	mux.HandleFunc("GET /state/grenades", gsi.withSnapshot(func(state *models.State) (interface{}, bool) {
+		if state.Grenades == nil {
+			return map[string]*models.Grenade{}, true
+		}
+		return state.Grenades, true
+	}))
+	mux.HandleFunc("GET /state/damage", gsi.withSnapshot(func(state *models.State) (interface{}, bool) {
+		if state.Damage == nil {
+			return []models.RoundDamage{}, true
+		}
+		return state.Damage, true
+	}))
+	mux.HandleFunc("GET /state/schema", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.Header().Set("X-Schema-Version", models.SchemaVersion)
+		w.Write(models.JSONSchema)
+	})
+	return mux
+}
+
+// withSnapshot adapts a view of the snapshot into a handler. The view
+// reports false when the requested resource does not exist.
+func (gsi *CS2GSI) withSnapshot(view func(state *models.State) (interface{}, bool)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		state := gsi.Snapshot()
+		if state == nil {
+			writeJSONError(w, http.StatusServiceUnavailable, "no game state received yet")
+			return
+		}
+
+		body, ok := view(state)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "not found")
+			return
+		}
+
+		if err := writeJSON(w, http.StatusOK, body); err != nil {
+			gsi.logger.Error("failed to write state response", "error", err, "path", r.URL.Path)
+		}
+	}
+}
+
+// sortedPlayers returns the players ordered by observer slot, then steam id
+func sortedPlayers(players map[string]*models.Player) []*models.Player {
+	out := make([]*models.Player, 0, len(players))
+	for _, player := range players {
+		out = append(out, player)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Observer_slot != out[j].Observer_slot {
+			return out[i].Observer_slot < out[j].Observer_slot
+		}
+		return out[i].SteamId < out[j].SteamId
+	})
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Schema-Version", models.SchemaVersion)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(body)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
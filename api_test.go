@@ -0,0 +1,74 @@
+package cs2gsi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func getJSON(t *testing.T, url string, target interface{}) int {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("get %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if target != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+			t.Fatalf("decode %s: %v", url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestStateAPI(t *testing.T) {
+	gsi := New(Config{Bus: NewBus()})
+	srv := httptest.NewServer(gsi.StateHandler())
+	defer srv.Close()
+
+	if status := getJSON(t, srv.URL+"/state", nil); status != http.StatusServiceUnavailable {
+		t.Fatalf("status before first state = %d, want 503", status)
+	}
+
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	var players []map[string]interface{}
+	if status := getJSON(t, srv.URL+"/state/players", &players); status != http.StatusOK {
+		t.Fatalf("players status = %d", status)
+	}
+	if len(players) != 1 {
+		t.Fatalf("players = %d, want 1", len(players))
+	}
+
+	var player map[string]interface{}
+	if status := getJSON(t, srv.URL+"/state/players/76561198000000001", &player); status != http.StatusOK {
+		t.Fatalf("player status = %d", status)
+	}
+	if player["steamId"] != "76561198000000001" {
+		t.Fatalf("player steamId = %v", player["steamId"])
+	}
+	if status := getJSON(t, srv.URL+"/state/players/1", nil); status != http.StatusNotFound {
+		t.Fatalf("unknown player status = %d, want 404", status)
+	}
+
+	var rounds []map[string]interface{}
+	if status := getJSON(t, srv.URL+"/state/rounds", &rounds); status != http.StatusOK || len(rounds) != 1 {
+		t.Fatalf("rounds status = %d, len = %d; want 200 with 1 round", status, len(rounds))
+	}
+
+	for _, path := range []string{"/state", "/state/bomb", "/state/grenades", "/state/damage", "/state/schema"} {
+		if status := getJSON(t, srv.URL+path, nil); status != http.StatusOK {
+			t.Fatalf("%s status = %d, want 200", path, status)
+		}
+	}
+}
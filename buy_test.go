@@ -0,0 +1,99 @@
+package cs2gsi_test
+
+import (
+	"reflect"
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	"github.com/nescabir/go-cs2-gsi/gsitest"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+var cts = []string{ct1, ct2, "76561198000000003", "76561198000000004", "76561198000000005"}
+
+// freezetime is the freezetime of the round after round rounds were played,
+// with five CTs who have the same money, equipment value, armor and primary
+func freezetime(round, money, equip, armor int, primary string) *gsitest.Payload {
+	p := gsitest.NewPayload().Map("de_dust2", round).
+		RoundPhase(models.RoundPhaseFreezeTime).Countdown(models.PhaseTypeFreezetime, 15)
+	for _, id := range cts {
+		p.Player(id).Team(models.CTSide).Money(money).EquipValue(equip).Armor(armor).
+			Weapon("weapon_usp_silencer", models.WeaponTypePistol, models.WeaponStateActive)
+		if primary != "" {
+			p.Weapon(primary, weaponTypes[primary], models.WeaponStateHolstered)
+		}
+	}
+	return p
+}
+
+// ctBuy ends the freezetime of p and returns the CT buy summary
+func ctBuy(t *testing.T, p *gsitest.Payload) *models.BuySummaryEvent {
+	t.Helper()
+	gsi, rec := newRecorded(t)
+	p.Digest(t, gsi)
+	p.Clone().RoundPhase(models.RoundPhaseLive).Countdown(models.PhaseTypeLive, 115).Digest(t, gsi)
+
+	for _, event := range gsitest.EventsOf(rec, cs2gsi.BuySummary) {
+		if event.Data.Team.Side == models.CTSide {
+			return event.Data
+		}
+	}
+	t.Fatalf("no CT buy summary (recorded: %q)", rec.Names())
+	return nil
+}
+
+func TestBuyClassification(t *testing.T) {
+	tests := []struct {
+		name         string
+		round        int
+		money, equip int
+		armor        int
+		primary      string
+		want         models.BuyType
+	}{
+		{"first pistol round", 0, 0, 850, 100, "", models.BuyTypePistol},
+		{"second half pistol round", 12, 0, 850, 100, "", models.BuyTypePistol},
+		{"eco", 3, 2400, 700, 0, "", models.BuyTypeEco},
+		{"force", 3, 300, 2700, 100, "weapon_mp9", models.BuyTypeForce},
+		{"half-buy", 3, 2500, 2700, 100, "weapon_mp9", models.BuyTypeHalfBuy},
+		{"full buy", 3, 300, 5200, 100, "weapon_m4a1", models.BuyTypeFullBuy},
+		{"overtime", 24, 800, 5200, 100, "weapon_m4a1", models.BuyTypeFullBuy},
+		{"full buy without armor", 3, 300, 4500, 0, "weapon_m4a1", models.BuyTypeForce},
+		{"pistols and armor only", 3, 300, 4200, 100, "", models.BuyTypeForce},
+		{"pistols and utility only", 3, 300, 4200, 0, "", models.BuyTypeHalfBuy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buy := ctBuy(t, freezetime(tt.round, tt.money, tt.equip, tt.armor, tt.primary))
+			if buy.Type != tt.want {
+				t.Fatalf("buy = %s, want %s", buy.Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuySummaryTotals(t *testing.T) {
+	p := freezetime(5, 1000, 4500, 0, "").
+		Player(ct1).Armor(100).Helmet(true).DefuseKit(true).
+		Weapon("weapon_m4a1", models.WeaponTypeRifle, models.WeaponStateHolstered).
+		Player(ct2).Armor(100).
+		Weapon("weapon_awp", models.WeaponTypeSniperRifle, models.WeaponStateHolstered).
+		// A T is left out of the CT summary
+		Player(t1).Team(models.TSide).Money(16000)
+
+	got := ctBuy(t, p)
+	want := &models.BuySummaryEvent{
+		Team:       got.Team,
+		Players:    5,
+		Money:      5000,
+		EquipValue: 22500,
+		Armor:      2,
+		Helmets:    1,
+		DefuseKits: 1,
+		Primaries:  []string{"weapon_awp", "weapon_m4a1"},
+		Type:       models.BuyTypeHalfBuy,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("summary = %+v, want %+v", got, want)
+	}
+}
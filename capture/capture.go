@@ -0,0 +1,125 @@
+// Package capture records the payloads received by a CS2GSI instance to
+// NDJSON files, one entry per line, so that they can be replayed later.
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Source is the feed an entry was received on
+type Source string
+
+const (
+	// SourceGSI is a game state payload passed to Digest
+	SourceGSI Source = "gsi"
+	// SourceMIRV is an HLAE game event passed to DigestMIRV
+	SourceMIRV Source = "mirv"
+)
+
+// ErrInvalidPayload is returned when a payload is not valid JSON
+var ErrInvalidPayload = errors.New("capture payload is not valid JSON")
+
+// Entry is one recorded payload
+type Entry struct {
+	// Time is when the payload was received
+	Time time.Time `json:"time"`
+	// Source is the feed the payload was received on
+	Source Source `json:"source"`
+	// Type is the MIRV event type, empty for GSI payloads
+	Type string `json:"type,omitempty"`
+	// Payload is the body as received
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Writer appends entries to an NDJSON stream
+type Writer struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewWriter returns a Writer appending to w
+func NewWriter(w io.Writer) *Writer {
+	bw := bufio.NewWriter(w)
+	return &Writer{w: bw, enc: json.NewEncoder(bw)}
+}
+
+// Write appends an entry and flushes it to the underlying writer
+func (w *Writer) Write(entry Entry) error {
+	if !json.Valid(entry.Payload) {
+		return ErrInvalidPayload
+	}
+	if err := w.enc.Encode(entry); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+// maxLineSize bounds a single capture line; full GSI payloads with every
+// data feed enabled stay well below it
+const maxLineSize = 16 * 1024 * 1024
+
+// Reader reads entries from an NDJSON stream
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewReader returns a Reader consuming r
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &Reader{scanner: scanner}
+}
+
+// Next returns the next entry, or io.EOF at the end of the stream. Blank
+// lines are skipped.
+func (r *Reader) Next() (Entry, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return Entry{}, fmt.Errorf("capture line %d: %w", r.line, err)
+		}
+		return entry, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Entry{}, err
+	}
+	return Entry{}, io.EOF
+}
+
+// ReadAll reads every remaining entry
+func (r *Reader) ReadAll() ([]Entry, error) {
+	var entries []Entry
+	for {
+		entry, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// ReadFile reads every entry of a capture file
+func ReadFile(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return NewReader(file).ReadAll()
+}
@@ -0,0 +1,57 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriterReaderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: at, Source: SourceGSI, Payload: json.RawMessage(`{"map":{"name":"de_mirage"}}`)},
+		{Time: at.Add(time.Second), Source: SourceMIRV, Type: "player_death", Payload: json.RawMessage(`{"name":"player_death"}`)},
+	}
+	for _, entry := range entries {
+		if err := w.Write(entry); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Write(Entry{Source: SourceGSI, Payload: []byte("not json")}); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("Write invalid payload error = %v, want ErrInvalidPayload", err)
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Fatalf("lines = %d, want 2", lines)
+	}
+
+	got, err := NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("entries = %d, want 2", len(got))
+	}
+	if !got[0].Time.Equal(at) || got[0].Source != SourceGSI || got[0].Type != "" {
+		t.Fatalf("entry 0 = %+v", got[0])
+	}
+	if got[1].Source != SourceMIRV || got[1].Type != "player_death" || string(got[1].Payload) != `{"name":"player_death"}` {
+		t.Fatalf("entry 1 = %+v", got[1])
+	}
+}
+
+func TestReaderInvalidLine(t *testing.T) {
+	r := NewReader(strings.NewReader("\n{\"source\":\"gsi\",\"payload\":{}}\nnope\n"))
+	if _, err := r.Next(); err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("Next error = %v, want line 3 error", err)
+	}
+}
@@ -0,0 +1,272 @@
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// Rotation decides when the recorder starts a new capture file
+type Rotation int
+
+const (
+	// RotateNever writes everything to a single file
+	RotateNever Rotation = iota
+	// RotatePerMap starts a new file whenever the map changes
+	RotatePerMap
+	// RotatePerMatch starts a new file whenever the map changes or the match
+	// restarts on the same map (back to warmup, or live again after gameover)
+	RotatePerMatch
+)
+
+// RecorderOptions configures a Recorder
+type RecorderOptions struct {
+	// Dir is the directory capture files are created in. Files are named
+	// capture-<utc time>-<map>.ndjson.
+	Dir string
+	// Writer, when set, receives every entry instead of files in Dir and
+	// Rotate is ignored
+	Writer io.Writer
+	// Rotate selects when a new file is started
+	Rotate Rotation
+	// OnError is called when an entry cannot be recorded. Defaults to
+	// logging with slog.
+	OnError func(err error)
+}
+
+// Recorder appends every payload received by an instance to a capture. It
+// records GSI payloads from the Raw event and MIRV events from RawMIRV,
+// including payloads rejected after decoding, e.g. for a wrong token, so
+// that a replay reproduces what the instance saw. Payloads that are not
+// valid JSON cannot be recorded and are passed to OnError. The auth block
+// is left out so the token is never written to disk.
+type Recorder struct {
+	opts RecorderOptions
+	sub  *cs2gsi.Subscription
+
+	mu      sync.Mutex
+	closed  bool
+	file    *os.File
+	path    string
+	writer  *Writer
+	mapName string
+	phase   models.MapPhase
+}
+
+// NewRecorder starts recording the payloads published by src
+func NewRecorder(src cs2gsi.EventSource, opts RecorderOptions) (*Recorder, error) {
+	if opts.Writer == nil {
+		if opts.Dir == "" {
+			opts.Dir = "."
+		}
+		if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create capture directory: %w", err)
+		}
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(err error) {
+			slog.Error("failed to record payload", "error", err)
+		}
+	}
+
+	r := &Recorder{opts: opts}
+	if opts.Writer != nil {
+		r.writer = NewWriter(opts.Writer)
+	}
+	// A single wildcard subscription keeps GSI and MIRV entries in arrival
+	// order, also on an asynchronous bus
+	r.sub = cs2gsi.SubscribeAllTo(src, r.handle)
+	return r, nil
+}
+
+func (r *Recorder) handle(event cs2gsi.GameEvent) {
+	var entry Entry
+	switch e := event.(type) {
+	case cs2gsi.Event[[]byte]:
+		if e.Name != string(models.Raw) {
+			return
+		}
+		entry = Entry{Time: e.Time, Source: SourceGSI, Payload: withoutAuth(e.Data)}
+	case cs2gsi.Event[*models.MIRVPayload]:
+		if e.Data == nil {
+			return
+		}
+		entry = Entry{Time: e.Time, Source: SourceMIRV, Type: e.Data.Type, Payload: withoutAuth(e.Data.Payload)}
+	default:
+		return
+	}
+
+	if err := r.Record(entry); err != nil {
+		r.opts.OnError(err)
+	}
+}
+
+// withoutAuth removes the auth block from a JSON object payload. Other
+// payloads are returned unchanged.
+func withoutAuth(payload []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload
+	}
+	if _, ok := fields["auth"]; !ok {
+		return payload
+	}
+	delete(fields, "auth")
+	stripped, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+	return stripped
+}
+
+// Record appends an entry, rotating the capture file first when needed
+func (r *Recorder) Record(entry Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return os.ErrClosed
+	}
+
+	if r.opts.Writer == nil {
+		if err := r.rotate(entry); err != nil {
+			return err
+		}
+	}
+	return r.writer.Write(entry)
+}
+
+// rotate opens the file the entry belongs in
+func (r *Recorder) rotate(entry Entry) error {
+	mapName, phase := payloadMap(entry)
+
+	next := r.file == nil
+	if !next && mapName != "" {
+		switch r.opts.Rotate {
+		case RotatePerMap:
+			next = r.mapName != "" && mapName != r.mapName
+		case RotatePerMatch:
+			next = (r.mapName != "" && mapName != r.mapName) || newMatch(r.phase, phase)
+		}
+	}
+
+	if mapName != "" {
+		if r.mapName == "" && !next {
+			r.mapName = mapName
+		}
+		if phase != "" {
+			r.phase = phase
+		}
+	}
+	if !next {
+		return nil
+	}
+
+	if err := r.closeFile(); err != nil {
+		return err
+	}
+	file, err := createCaptureFile(r.opts.Dir, entry, mapName)
+	if err != nil {
+		return err
+	}
+	r.file = file
+	r.path = file.Name()
+	r.writer = NewWriter(file)
+	r.mapName = mapName
+	return nil
+}
+
+// newMatch reports whether a phase change starts a new match on the same map
+func newMatch(last, current models.MapPhase) bool {
+	if last == "" || current == "" {
+		return false
+	}
+	if current == models.MapPhaseWarmup && last != models.MapPhaseWarmup {
+		return true
+	}
+	return last == models.MapPhaseGameOver && current != models.MapPhaseGameOver
+}
+
+// payloadMap reads the map name and phase of a GSI payload. Payloads
+// without a map block, and MIRV events, return empty values.
+func payloadMap(entry Entry) (string, models.MapPhase) {
+	if entry.Source != SourceGSI {
+		return "", ""
+	}
+	var payload struct {
+		Map *struct {
+			Name  string          `json:"name"`
+			Phase models.MapPhase `json:"phase"`
+		} `json:"map"`
+	}
+	if err := json.Unmarshal(entry.Payload, &payload); err != nil || payload.Map == nil {
+		return "", ""
+	}
+	return payload.Map.Name, payload.Map.Phase
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// createCaptureFile creates a new file named after the entry time and map,
+// adding a counter when the name is already taken
+func createCaptureFile(dir string, entry Entry, mapName string) (*os.File, error) {
+	base := "capture-" + entry.Time.UTC().Format("20060102-150405")
+	if mapName != "" {
+		base += "-" + unsafeFileChars.ReplaceAllString(mapName, "_")
+	}
+
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		file, err := os.OpenFile(filepath.Join(dir, name+".ndjson"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create capture file: %w", err)
+		}
+		return file, nil
+	}
+}
+
+// Path returns the capture file currently written to, or an empty string
+// when recording to RecorderOptions.Writer or before the first entry
+func (r *Recorder) Path() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.path
+}
+
+// Close stops recording and closes the current capture file
+func (r *Recorder) Close() error {
+	r.sub.Unsubscribe()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.closeFile()
+}
+
+func (r *Recorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,103 @@
+package capture
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+)
+
+func readFixture(t *testing.T, path string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "testdata", path))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return data
+}
+
+func TestRecorderWritesGSIAndMIRV(t *testing.T) {
+	gsi := cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus()})
+
+	var buf bytes.Buffer
+	rec, err := NewRecorder(gsi, RecorderOptions{Writer: &buf})
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+
+	if err := gsi.Digest(readFixture(t, "gsi/with_deltas.json")); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	if _, err := gsi.DigestMIRV(readFixture(t, "mirv/player_death.json"), cs2gsi.MIRVEventPlayerDeath); err != nil {
+		t.Fatalf("DigestMIRV: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := gsi.Digest(readFixture(t, "gsi/with_deltas.json")); err != nil {
+		t.Fatalf("Digest after Close: %v", err)
+	}
+
+	entries, err := NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(entries))
+	}
+	if entries[0].Source != SourceGSI || entries[0].Time.IsZero() {
+		t.Fatalf("entry 0 = %+v", entries[0])
+	}
+	if entries[1].Source != SourceMIRV || entries[1].Type != cs2gsi.MIRVEventPlayerDeath {
+		t.Fatalf("entry 1 = %+v", entries[1])
+	}
+	if bytes.Contains(entries[0].Payload, []byte(`"auth"`)) {
+		t.Fatalf("recorded payload kept the auth block: %s", entries[0].Payload)
+	}
+}
+
+func TestRecorderRotation(t *testing.T) {
+	payload := func(mapName, phase string) []byte {
+		return []byte(`{"map":{"name":"` + mapName + `","phase":"` + phase + `"}}`)
+	}
+
+	cases := []struct {
+		rotate Rotation
+		want   int
+	}{
+		{RotateNever, 1},
+		{RotatePerMap, 2},
+		{RotatePerMatch, 3},
+	}
+	for _, tc := range cases {
+		dir := t.TempDir()
+		rec, err := NewRecorder(cs2gsi.NewBus(), RecorderOptions{Dir: dir, Rotate: tc.rotate})
+		if err != nil {
+			t.Fatalf("NewRecorder: %v", err)
+		}
+
+		for _, p := range [][]byte{
+			payload("de_mirage", "live"),
+			payload("de_mirage", "gameover"),
+			payload("de_mirage", "warmup"),
+			payload("de_inferno", "warmup"),
+		} {
+			if err := rec.Record(Entry{Source: SourceGSI, Payload: p}); err != nil {
+				t.Fatalf("Record: %v", err)
+			}
+		}
+		if !strings.Contains(filepath.Base(rec.Path()), "de_") {
+			t.Fatalf("path %q does not name the map", rec.Path())
+		}
+		rec.Close()
+
+		files, _ := filepath.Glob(filepath.Join(dir, "*.ndjson"))
+		if len(files) != tc.want {
+			t.Errorf("rotation %d: files = %d, want %d", tc.rotate, len(files), tc.want)
+		}
+	}
+}
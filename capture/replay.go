@@ -0,0 +1,290 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// ErrRoundNotFound is returned by SeekRound when the capture never reaches
+// the requested round
+var ErrRoundNotFound = errors.New("round not found in capture")
+
+// ReplayOptions configures a Replayer
+type ReplayOptions struct {
+	// Speed scales the recorded pace: 1 replays in real time, 2 twice as
+	// fast. Zero or less replays as fast as possible.
+	Speed float64
+	// OnError is called when Digest or DigestMIRV rejects an entry. The
+	// replay continues; live instances reject the same payloads, so errors
+	// are ignored when nil.
+	OnError func(entry Entry, err error)
+}
+
+// Replayer feeds captured entries back through Digest and DigestMIRV. Run
+// drives the replay; Pause, Resume, Step and SeekRound control it and may
+// be called from other goroutines. Step and SeekRound must not be called
+// from event handlers of the replayed instance.
+type Replayer struct {
+	gsi     *cs2gsi.CS2GSI
+	entries []Entry
+	rounds  []int
+	opts    ReplayOptions
+
+	// feedMu serializes feeding; it is held while handlers run, so mu is
+	// kept separate for Pause and Resume to be callable from handlers
+	feedMu sync.Mutex
+
+	mu       sync.Mutex
+	pos      int
+	paused   bool
+	lastFeed time.Time
+	wake     chan struct{}
+}
+
+// NewReplayer returns a replayer feeding entries to gsi, positioned at the
+// first entry. Entries should come from a single capture, in order.
+func NewReplayer(gsi *cs2gsi.CS2GSI, entries []Entry, opts ReplayOptions) *Replayer {
+	return &Replayer{
+		gsi:     gsi,
+		entries: entries,
+		rounds:  entryRounds(entries),
+		opts:    opts,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// entryRounds returns the round of each entry, numbered like Event.Round:
+// the round in progress, or the round just finished while it is over.
+// Entries without a map block, such as MIRV events, belong to the round of
+// the entry before them.
+func entryRounds(entries []Entry) []int {
+	rounds := make([]int, len(entries))
+	round := 0
+	for i, entry := range entries {
+		if entry.Source == SourceGSI {
+			var payload struct {
+				Map *struct {
+					Round int             `json:"round"`
+					Phase models.MapPhase `json:"phase"`
+				} `json:"map"`
+				Round *struct {
+					Phase models.RoundPhase `json:"phase"`
+				} `json:"round"`
+			}
+			if err := json.Unmarshal(entry.Payload, &payload); err == nil && payload.Map != nil {
+				// map.round counts completed rounds
+				round = payload.Map.Round + 1
+				if (payload.Round != nil && payload.Round.Phase == models.RoundPhaseOver) || payload.Map.Phase == models.MapPhaseGameOver {
+					round = payload.Map.Round
+				}
+			}
+		}
+		rounds[i] = round
+	}
+	return rounds
+}
+
+// Run replays the remaining entries at the configured pace until the end of
+// the capture or until ctx is done. It resumes a paused replay and blocks
+// while the replay is paused again.
+func (r *Replayer) Run(ctx context.Context) error {
+	r.Resume()
+
+	for {
+		r.mu.Lock()
+		if r.pos >= len(r.entries) {
+			r.mu.Unlock()
+			return nil
+		}
+		paused := r.paused
+		delay := r.delayLocked()
+		r.mu.Unlock()
+
+		if paused {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-r.wake:
+			}
+			continue
+		}
+
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-r.wake:
+				// A control changed the position or pace; start over
+				timer.Stop()
+				continue
+			case <-timer.C:
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r.feedNext(false)
+	}
+}
+
+// delayLocked returns how long to wait before the next entry
+func (r *Replayer) delayLocked() time.Duration {
+	if r.opts.Speed <= 0 || r.pos == 0 || r.lastFeed.IsZero() {
+		return 0
+	}
+	gap := r.entries[r.pos].Time.Sub(r.entries[r.pos-1].Time)
+	due := r.lastFeed.Add(time.Duration(float64(gap) / r.opts.Speed))
+	return time.Until(due)
+}
+
+// feedNext feeds the entry at the current position and reports whether one
+// was fed. Unless evenIfPaused is set, nothing is fed while paused.
+func (r *Replayer) feedNext(evenIfPaused bool) bool {
+	r.feedMu.Lock()
+	defer r.feedMu.Unlock()
+
+	r.mu.Lock()
+	if r.pos >= len(r.entries) || (r.paused && !evenIfPaused) {
+		r.mu.Unlock()
+		return false
+	}
+	entry := r.entries[r.pos]
+	r.pos++
+	r.lastFeed = time.Now()
+	r.mu.Unlock()
+
+	r.feed(entry)
+	return true
+}
+
+func (r *Replayer) feed(entry Entry) {
+	var err error
+	switch entry.Source {
+	case SourceGSI:
+		err = r.gsi.Digest(entry.Payload)
+	case SourceMIRV:
+		_, err = r.gsi.DigestMIRV(entry.Payload, entry.Type)
+	default:
+		err = fmt.Errorf("unknown capture source %q", entry.Source)
+	}
+	if err != nil && r.opts.OnError != nil {
+		r.opts.OnError(entry, err)
+	}
+}
+
+// Pause stops Run before the next entry
+func (r *Replayer) Pause() {
+	r.mu.Lock()
+	r.paused = true
+	r.mu.Unlock()
+	r.signal()
+}
+
+// Resume continues a paused replay. The pace is measured from the moment
+// of resuming, so paused time is not caught up.
+func (r *Replayer) Resume() {
+	r.mu.Lock()
+	if r.paused {
+		r.paused = false
+		r.lastFeed = time.Now()
+	}
+	r.mu.Unlock()
+	r.signal()
+}
+
+// Paused reports whether the replay is paused
+func (r *Replayer) Paused() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.paused
+}
+
+// Step feeds the next entry immediately, whether or not the replay is
+// paused. It returns io.EOF at the end of the capture.
+func (r *Replayer) Step() error {
+	if !r.feedNext(true) {
+		return io.EOF
+	}
+	r.signal()
+	return nil
+}
+
+// SeekRound moves the replay to the first entry of a round. Entries up to
+// it are fed as fast as possible so that the instance reaches the same
+// state it had live, which also publishes their events. Seeking backwards
+// resets the instance and replays from the start of the capture.
+func (r *Replayer) SeekRound(round int) error {
+	target := -1
+	for i, entryRound := range r.rounds {
+		if entryRound >= round {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return fmt.Errorf("%w: %d", ErrRoundNotFound, round)
+	}
+
+	r.feedMu.Lock()
+	defer r.feedMu.Unlock()
+
+	r.mu.Lock()
+	pos := r.pos
+	r.mu.Unlock()
+
+	if target < pos {
+		r.gsi.Reset()
+		pos = 0
+	}
+	for ; pos < target; pos++ {
+		r.feed(r.entries[pos])
+	}
+
+	r.mu.Lock()
+	r.pos = pos
+	r.lastFeed = time.Now()
+	r.mu.Unlock()
+	r.signal()
+	return nil
+}
+
+// Position returns the index of the next entry and the total entry count
+func (r *Replayer) Position() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pos, len(r.entries)
+}
+
+// Round returns the round of the next entry, or of the last one once the
+// capture is done
+func (r *Replayer) Round() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.rounds) == 0 {
+		return 0
+	}
+	if r.pos >= len(r.rounds) {
+		return r.rounds[len(r.rounds)-1]
+	}
+	return r.rounds[r.pos]
+}
+
+// signal wakes Run after a control call
+func (r *Replayer) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
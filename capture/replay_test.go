@@ -0,0 +1,159 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// roundEntries builds one GSI entry per round from the fixture, gap apart
+func roundEntries(t *testing.T, rounds int, gap time.Duration) []Entry {
+	t.Helper()
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(readFixture(t, "gsi/with_deltas.json"), &payload); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+	delete(payload, "previously")
+	delete(payload, "added")
+
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := make([]Entry, rounds)
+	for i := range entries {
+		payload["map"].(map[string]interface{})["round"] = i
+		data, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("encode payload: %v", err)
+		}
+		entries[i] = Entry{Time: start.Add(time.Duration(i) * gap), Source: SourceGSI, Payload: data}
+	}
+	return entries
+}
+
+func newReplayTarget() (*cs2gsi.CS2GSI, *atomic.Int32) {
+	gsi := cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus()})
+	var data atomic.Int32
+	cs2gsi.SubscribeTo(gsi, cs2gsi.Data, func(cs2gsi.Event[*models.State]) { data.Add(1) })
+	return gsi, &data
+}
+
+func TestReplayAsFastAsPossible(t *testing.T) {
+	gsi, data := newReplayTarget()
+	r := NewReplayer(gsi, roundEntries(t, 5, time.Hour), ReplayOptions{})
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if data.Load() != 5 {
+		t.Fatalf("data events = %d, want 5", data.Load())
+	}
+	if r.Round() != 5 {
+		t.Fatalf("round = %d, want 5", r.Round())
+	}
+}
+
+func TestReplayPacing(t *testing.T) {
+	gsi, _ := newReplayTarget()
+	r := NewReplayer(gsi, roundEntries(t, 3, 40*time.Millisecond), ReplayOptions{Speed: 2})
+
+	start := time.Now()
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("replay took %v, want at least 40ms at speed 2", elapsed)
+	}
+}
+
+func TestReplayStepAndSeek(t *testing.T) {
+	gsi, data := newReplayTarget()
+	r := NewReplayer(gsi, roundEntries(t, 5, time.Second), ReplayOptions{})
+
+	if err := r.Step(); err != nil {
+		t.Fatalf("Step: %v", err)
+	}
+	if pos, total := r.Position(); pos != 1 || total != 5 {
+		t.Fatalf("position = %d/%d, want 1/5", pos, total)
+	}
+
+	if err := r.SeekRound(4); err != nil {
+		t.Fatalf("SeekRound(4): %v", err)
+	}
+	if pos, _ := r.Position(); pos != 3 || r.Round() != 4 {
+		t.Fatalf("position = %d, round = %d; want 3, 4", pos, r.Round())
+	}
+	if data.Load() != 3 {
+		t.Fatalf("data events = %d, want 3", data.Load())
+	}
+	if got := gsi.Snapshot().Map.Round; got != 2 {
+		t.Fatalf("map round = %d, want 2", got)
+	}
+
+	if err := r.SeekRound(1); err != nil {
+		t.Fatalf("SeekRound(1): %v", err)
+	}
+	if gsi.Snapshot() != nil {
+		t.Fatal("seeking back to the start should reset the instance")
+	}
+
+	if err := r.SeekRound(10); !errors.Is(err, ErrRoundNotFound) {
+		t.Fatalf("SeekRound(10) error = %v, want ErrRoundNotFound", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := r.Step(); err != nil {
+			t.Fatalf("Step %d: %v", i, err)
+		}
+	}
+	if err := r.Step(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Step at end error = %v, want io.EOF", err)
+	}
+}
+
+func TestReplayPauseResume(t *testing.T) {
+	gsi, data := newReplayTarget()
+	r := NewReplayer(gsi, roundEntries(t, 3, 20*time.Millisecond), ReplayOptions{Speed: 1})
+
+	// Pause from a handler once the first entry has been fed
+	sub := cs2gsi.SubscribeOnceTo(gsi, cs2gsi.Data, func(cs2gsi.Event[*models.State]) { r.Pause() })
+	defer sub.Unsubscribe()
+
+	done := make(chan error, 1)
+	go func() { done <- r.Run(context.Background()) }()
+
+	time.Sleep(100 * time.Millisecond)
+	if !r.Paused() || data.Load() != 1 {
+		t.Fatalf("paused = %v, data events = %d; want paused after 1", r.Paused(), data.Load())
+	}
+
+	r.Resume()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("replay did not finish after Resume")
+	}
+	if data.Load() != 3 {
+		t.Fatalf("data events = %d, want 3", data.Load())
+	}
+}
+
+func TestReplayContextCancel(t *testing.T) {
+	gsi, _ := newReplayTarget()
+	r := NewReplayer(gsi, roundEntries(t, 3, time.Hour), ReplayOptions{Speed: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := r.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,79 @@
+package cs2gsi
+
+import "sync"
+
+// Channel subscribes to eventName on the bus of src and returns a channel
+// receiving its events. When the channel buffer is full new events are
+// dropped and counted on the returned subscription. The channel is closed
+// once the subscription is removed.
+func Channel[T any](src EventSource, eventName EventName[T], bufSize int) (<-chan Event[T], *Subscription) {
+	ch := make(chan Event[T], bufSize)
+	forward := newChannelForwarder(ch)
+
+	sub := SubscribeTo(src, eventName, func(event Event[T]) {
+		forward.send(event)
+	})
+	forward.attach(sub)
+
+	return ch, sub
+}
+
+// Events subscribes to every event on the bus of src and returns a single
+// stream of them. Buffering and closing behave as for Channel.
+func Events(src EventSource, bufSize int) (<-chan GameEvent, *Subscription) {
+	ch := make(chan GameEvent, bufSize)
+	forward := newChannelForwarder(ch)
+
+	sub := SubscribeAllTo(src, func(event GameEvent) {
+		forward.send(event)
+	})
+	forward.attach(sub)
+
+	return ch, sub
+}
+
+// channelForwarder moves events from a bus handler into a channel without
+// ever blocking the publisher, and closes the channel on unsubscribe
+type channelForwarder[T any] struct {
+	mu     sync.Mutex
+	ch     chan T
+	sub    *Subscription
+	closed bool
+}
+
+func newChannelForwarder[T any](ch chan T) *channelForwarder[T] {
+	return &channelForwarder[T]{ch: ch}
+}
+
+// attach binds the forwarder to its subscription and closes the channel
+// once the subscription is removed
+func (f *channelForwarder[T]) attach(sub *Subscription) {
+	f.mu.Lock()
+	f.sub = sub
+	f.mu.Unlock()
+
+	go func() {
+		<-sub.done
+		f.mu.Lock()
+		f.closed = true
+		close(f.ch)
+		f.mu.Unlock()
+	}()
+}
+
+func (f *channelForwarder[T]) send(event T) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return
+	}
+	select {
+	case f.ch <- event:
+	default:
+		if f.sub != nil {
+			f.sub.sub.dropped.Add(1)
+			f.sub.bus.dropped.Add(1)
+		}
+	}
+}
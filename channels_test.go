@@ -0,0 +1,65 @@
+package cs2gsi
+
+import (
+	"os"
+	"testing"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+func TestChannel(t *testing.T) {
+	gsi := New(Config{Bus: NewBus()})
+
+	ch, sub := Channel(gsi, BombPlanted, 1)
+
+	planter := &models.Player{Name: "Planter"}
+	gsi.publishBombPlanted(planter)
+	gsi.publishBombPlanted(planter)
+
+	event := <-ch
+	if event.Data != planter {
+		t.Fatalf("event data = %v, want planter", event.Data)
+	}
+	if sub.Dropped() != 1 {
+		t.Fatalf("dropped = %d, want 1", sub.Dropped())
+	}
+
+	sub.Unsubscribe()
+	if _, ok := <-ch; ok {
+		t.Fatal("expected channel to be closed after Unsubscribe")
+	}
+}
+
+func TestEventsStream(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{Bus: NewBus()})
+	events, sub := Events(gsi, 8)
+	defer sub.Unsubscribe()
+
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	var names []string
+	for len(names) < 2 {
+		switch event := (<-events).(type) {
+		case Event[[]byte]:
+			names = append(names, event.Name)
+		case Event[*models.State]:
+			if event.Data.Map.Name != "de_dust2" {
+				t.Fatalf("map = %q, want de_dust2", event.Data.Map.Name)
+			}
+			names = append(names, event.EventName())
+		default:
+			t.Fatalf("unexpected event %T", event)
+		}
+	}
+
+	if names[0] != string(models.Raw) || names[1] != string(models.Data) {
+		t.Fatalf("events = %v, want [raw data]", names)
+	}
+}
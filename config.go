@@ -2,6 +2,7 @@ package cs2gsi
 
 import (
 	"log/slog"
+	"time"
 
 	models "github.com/nescabir/go-cs2-gsi/models"
 )
@@ -11,6 +12,18 @@ type TeamExtensionsConfig struct {
 	Right *models.TeamExtension
 }
 
+// BuyThresholds classify a team's buy from the average equipment value
+// and money left per player at freezetime end
+type BuyThresholds struct {
+	// Eco is the equipment value below which a buy is an eco
+	Eco int
+	// FullBuy is the equipment value from which a buy is a full buy
+	FullBuy int
+	// ForceMoney splits the buys in between: a force buy leaves less than
+	// this much money, a half-buy keeps more for the next round
+	ForceMoney int
+}
+
 type Config struct {
 	RegulationMaxRounds int
 	OvertimeMaxRounds   int
@@ -19,6 +32,29 @@ type Config struct {
 	ExpectedToken       string
 	PlayerExtensions    []models.PlayerExtension
 	TeamExtensions      TeamExtensionsConfig
+	BuyThresholds       BuyThresholds
+	// OvertimeStartMoney is the server's mp_overtime_startmoney, used by the
+	// economy forecast
+	OvertimeStartMoney int
+	// Bus receives the events published by this instance. Instances sharing
+	// a bus see each other's events; nil uses DefaultBus.
+	Bus *Bus
+	// OnHandlerError receives panics recovered from event handlers. The
+	// panicking handler is skipped and processing continues; nil logs them.
+	OnHandlerError func(err *HandlerError)
+	// SessionFile, when set, is loaded by New if it exists and rewritten
+	// after every round, so a restarted process resumes the match where it
+	// left off. See SaveSession.
+	SessionFile string
+
+	// HTTP server timeouts used by Listen and ListenContext.
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout bounds how long ListenContext waits for in-flight
+	// requests to drain once its context is cancelled.
+	ShutdownTimeout time.Duration
 }
 
 // NewConfig creates a new Config with sensible defaults
@@ -28,6 +64,13 @@ func NewConfig() Config {
 		OvertimeMaxRounds:   3,
 		ServerAddr:          ":3000",
 		LogLevel:            slog.LevelInfo,
+		BuyThresholds:       BuyThresholds{Eco: 1500, FullBuy: 4000, ForceMoney: 1000},
+		OvertimeStartMoney:  12500,
+		ReadTimeout:         5 * time.Second,
+		ReadHeaderTimeout:   5 * time.Second,
+		WriteTimeout:        10 * time.Second,
+		IdleTimeout:         60 * time.Second,
+		ShutdownTimeout:     5 * time.Second,
 	}
 }
 
@@ -45,4 +88,31 @@ func (c *Config) SetDefaults() {
 	if c.LogLevel == 0 {
 		c.LogLevel = slog.LevelInfo
 	}
+	if c.BuyThresholds.Eco <= 0 {
+		c.BuyThresholds.Eco = 1500
+	}
+	if c.BuyThresholds.FullBuy <= 0 {
+		c.BuyThresholds.FullBuy = 4000
+	}
+	if c.BuyThresholds.ForceMoney <= 0 {
+		c.BuyThresholds.ForceMoney = 1000
+	}
+	if c.OvertimeStartMoney <= 0 {
+		c.OvertimeStartMoney = 12500
+	}
+	if c.ReadTimeout <= 0 {
+		c.ReadTimeout = 5 * time.Second
+	}
+	if c.ReadHeaderTimeout <= 0 {
+		c.ReadHeaderTimeout = 5 * time.Second
+	}
+	if c.WriteTimeout <= 0 {
+		c.WriteTimeout = 10 * time.Second
+	}
+	if c.IdleTimeout <= 0 {
+		c.IdleTimeout = 60 * time.Second
+	}
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = 5 * time.Second
+	}
 }
@@ -0,0 +1,526 @@
+// Package csgogsi converts the models of go-cs2-gsi into the JSON shapes
+// and event names of osztenkurden's csgogsi library, so that HUDs written
+// against csgogsi can be fed by this backend unchanged.
+package csgogsi
+
+import (
+	"sort"
+	"strconv"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// CSGO is the parsed game state, csgogsi's CSGO interface
+type CSGO struct {
+	Provider        *Provider       `json:"provider"`
+	Map             *Map            `json:"map"`
+	Round           *Round          `json:"round"`
+	Player          *Player         `json:"player"`
+	Observer        *Observer       `json:"observer"`
+	Players         []*Player       `json:"players"`
+	Bomb            *Bomb           `json:"bomb"`
+	Grenades        []*Grenade      `json:"grenades"`
+	PhaseCountdowns *PhaseCountdown `json:"phase_countdowns"`
+	// Auth is always null; the GSI token is not forwarded to HUDs
+	Auth *Auth `json:"auth"`
+}
+
+type Provider struct {
+	Name      string  `json:"name"`
+	AppID     int     `json:"appid"`
+	Version   int     `json:"version"`
+	SteamID   string  `json:"steamid"`
+	Timestamp float32 `json:"timestamp"`
+}
+
+type Map struct {
+	Mode                  string                         `json:"mode"`
+	Name                  string                         `json:"name"`
+	Phase                 models.MapPhase                `json:"phase"`
+	Round                 int                            `json:"round"`
+	TeamCT                *Team                          `json:"team_ct"`
+	TeamT                 *Team                          `json:"team_t"`
+	NumMatchesToWinSeries int                            `json:"num_matches_to_win_series"`
+	CurrentSpectators     int                            `json:"current_spectators"`
+	SouvenirsTotal        int                            `json:"souvenirs_total"`
+	RoundWins             map[string]models.RoundOutcome `json:"round_wins,omitempty"`
+	Rounds                []*RoundInfo                   `json:"rounds"`
+}
+
+type Round struct {
+	Phase   models.RoundPhase     `json:"phase"`
+	Bomb    models.BombRoundState `json:"bomb,omitempty"`
+	WinTeam models.Side           `json:"win_team,omitempty"`
+}
+
+type RoundInfo struct {
+	Team    *Team               `json:"team"`
+	Round   int                 `json:"round"`
+	Side    models.Side         `json:"side"`
+	Outcome models.RoundOutcome `json:"outcome"`
+}
+
+type Team struct {
+	Score                  int                `json:"score"`
+	Logo                   *string            `json:"logo"`
+	ConsecutiveRoundLosses int                `json:"consecutive_round_losses"`
+	TimeoutsRemaining      int                `json:"timeouts_remaining"`
+	MatchesWonThisSeries   int                `json:"matches_won_this_series"`
+	Side                   models.Side        `json:"side"`
+	Name                   string             `json:"name"`
+	Country                *string            `json:"country"`
+	ID                     *string            `json:"id"`
+	Orientation            models.Orientation `json:"orientation"`
+	Extra                  map[string]string  `json:"extra"`
+}
+
+type Player struct {
+	SteamID      string            `json:"steamid"`
+	Name         string            `json:"name"`
+	DefaultName  string            `json:"defaultName"`
+	Clan         string            `json:"clan,omitempty"`
+	ObserverSlot int               `json:"observer_slot"`
+	Team         *Team             `json:"team"`
+	State        *PlayerState      `json:"state"`
+	Stats        *PlayerStats      `json:"stats"`
+	Weapons      []*Weapon         `json:"weapons"`
+	Position     []float32         `json:"position"`
+	Forward      []float32         `json:"forward"`
+	Avatar       *string           `json:"avatar"`
+	Country      *string           `json:"country"`
+	RealName     *string           `json:"realName"`
+	Extra        map[string]string `json:"extra"`
+}
+
+type PlayerState struct {
+	Health        int  `json:"health"`
+	Armor         int  `json:"armor"`
+	Helmet        bool `json:"helmet"`
+	DefuseKit     bool `json:"defusekit"`
+	Flashed       int  `json:"flashed"`
+	Smoked        int  `json:"smoked"`
+	Burning       int  `json:"burning"`
+	Money         int  `json:"money"`
+	RoundKills    int  `json:"round_kills"`
+	RoundKillHS   int  `json:"round_killhs"`
+	RoundTotalDmg int  `json:"round_totaldmg"`
+	EquipValue    int  `json:"equip_value"`
+	ADR           int  `json:"adr"`
+}
+
+type PlayerStats struct {
+	Kills   int `json:"kills"`
+	Assists int `json:"assists"`
+	Deaths  int `json:"deaths"`
+	MVPs    int `json:"mvps"`
+	Score   int `json:"score"`
+}
+
+type Weapon struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	PaintKit    string             `json:"paintkit"`
+	Type        models.WeaponType  `json:"type,omitempty"`
+	AmmoClip    int                `json:"ammo_clip,omitempty"`
+	AmmoClipMax int                `json:"ammo_clip_max,omitempty"`
+	AmmoReserve int                `json:"ammo_reserve,omitempty"`
+	State       models.WeaponState `json:"state"`
+}
+
+type Observer struct {
+	Activity   models.PlayerActivity `json:"activity,omitempty"`
+	SpecTarget string                `json:"spectarget,omitempty"`
+	Position   []float32             `json:"position"`
+	Forward    []float32             `json:"forward"`
+}
+
+type Bomb struct {
+	State     models.BombState `json:"state"`
+	Countdown float32          `json:"countdown,omitempty"`
+	Player    *Player          `json:"player,omitempty"`
+	Site      *models.BombSite `json:"site"`
+	Position  []float32        `json:"position"`
+}
+
+type Grenade struct {
+	ID         string             `json:"id"`
+	Owner      string             `json:"owner"`
+	Type       models.GrenadeType `json:"type"`
+	Lifetime   float32            `json:"lifetime"`
+	Position   []float32          `json:"position,omitempty"`
+	Velocity   []float32          `json:"velocity,omitempty"`
+	EffectTime float32            `json:"effecttime,omitempty"`
+	Flames     []*Flame           `json:"flames,omitempty"`
+}
+
+type Flame struct {
+	ID       string    `json:"id"`
+	Position []float32 `json:"position"`
+}
+
+// PhaseCountdown keeps csgogsi's raw shape, where phase_ends_in is a string
+type PhaseCountdown struct {
+	Phase       models.PhaseType `json:"phase"`
+	PhaseEndsIn string           `json:"phase_ends_in"`
+}
+
+type Auth struct {
+	Token string `json:"token"`
+}
+
+type Score struct {
+	Winner *Team `json:"winner"`
+	Loser  *Team `json:"loser"`
+	Map    *Map  `json:"map"`
+	MapEnd bool  `json:"mapEnd"`
+}
+
+type KillEvent struct {
+	Killer        *Player `json:"killer"`
+	Victim        *Player `json:"victim"`
+	Assister      *Player `json:"assister"`
+	Flashed       bool    `json:"flashed"`
+	Headshot      bool    `json:"headshot"`
+	Weapon        string  `json:"weapon"`
+	Wallbang      bool    `json:"wallbang"`
+	AttackerBlind bool    `json:"attackerblind"`
+	ThruSmoke     bool    `json:"thrusmoke"`
+	NoScope       bool    `json:"noscope"`
+	AttackerInAir bool    `json:"attackerinair"`
+}
+
+type HurtEvent struct {
+	Attacker  *Player `json:"attacker"`
+	Victim    *Player `json:"victim"`
+	Health    int     `json:"health"`
+	Armor     int     `json:"armor"`
+	Weapon    string  `json:"weapon"`
+	DmgHealth int     `json:"dmg_health"`
+	DmgArmor  int     `json:"dmg_armor"`
+	HitGroup  int     `json:"hitgroup"`
+}
+
+// eventNames lists the events whose csgogsi name differs from ours
+var eventNames = map[models.Events]string{
+	models.DefuseEnd:    "defuseStop",
+	models.BombExploded: "bombExplode",
+}
+
+// EventName returns the csgogsi name of an event. Events csgogsi does not
+// know, such as BombPlantStop, keep their name.
+func EventName(name models.Events) string {
+	if mapped, ok := eventNames[name]; ok {
+		return mapped
+	}
+	return string(name)
+}
+
+// Convert returns the csgogsi shape of an event payload. Payload types
+// without a csgogsi counterpart are returned unchanged.
+func Convert(data interface{}) interface{} {
+	switch v := data.(type) {
+	case *models.State:
+		return FromState(v)
+	case *models.Player:
+		return FromPlayer(v)
+	case *models.Team:
+		return FromTeam(v)
+	case *models.Score:
+		return FromScore(v)
+	case *models.KillEvent:
+		return FromKill(v)
+	case *models.HurtEvent:
+		return FromHurt(v)
+	}
+	return data
+}
+
+// FromState converts a game state. Players are ordered by observer slot.
+func FromState(s *models.State) *CSGO {
+	if s == nil {
+		return nil
+	}
+
+	out := &CSGO{
+		Map:      FromMap(s.Map),
+		Player:   FromPlayer(s.Player),
+		Observer: fromObserver(s.Observer),
+		Players:  fromPlayers(s.AllPlayers),
+		Bomb:     fromBomb(s.Bomb),
+		Grenades: fromGrenades(s.Grenades),
+	}
+	if s.Provider != nil {
+		out.Provider = &Provider{
+			Name:      s.Provider.Name,
+			AppID:     s.Provider.AppId,
+			Version:   s.Provider.Version,
+			SteamID:   s.Provider.SteamId,
+			Timestamp: s.Provider.Timestamp,
+		}
+	}
+	if s.Round != nil {
+		out.Round = &Round{Phase: s.Round.Phase, Bomb: s.Round.Bomb, WinTeam: s.Round.Win_team}
+	}
+	if s.Phase_countdowns != nil {
+		out.PhaseCountdowns = &PhaseCountdown{
+			Phase:       s.Phase_countdowns.Phase,
+			PhaseEndsIn: strconv.FormatFloat(float64(s.Phase_countdowns.Phase_ends_in), 'f', 1, 32),
+		}
+	}
+	return out
+}
+
+// FromMap converts the map information, including the round history
+func FromMap(m *models.Map) *Map {
+	if m == nil {
+		return nil
+	}
+
+	rounds := make([]*RoundInfo, 0, len(m.Rounds))
+	for _, r := range m.Rounds {
+		rounds = append(rounds, &RoundInfo{Team: FromTeam(r.Team), Round: r.Round, Side: r.Side, Outcome: r.Outcome})
+	}
+
+	return &Map{
+		Mode:                  m.Mode,
+		Name:                  m.Name,
+		Phase:                 m.Phase,
+		Round:                 m.Round,
+		TeamCT:                FromTeam(m.Team_ct),
+		TeamT:                 FromTeam(m.Team_t),
+		NumMatchesToWinSeries: m.Num_matches_to_win_series,
+		CurrentSpectators:     m.Current_spectators,
+		SouvenirsTotal:        m.Souvenirs_total,
+		RoundWins:             m.Round_wins,
+		Rounds:                rounds,
+	}
+}
+
+// FromTeam converts a team. Empty logo, country and id become null.
+func FromTeam(t *models.Team) *Team {
+	if t == nil {
+		return nil
+	}
+	return &Team{
+		Score:                  t.Score,
+		Logo:                   nullable(t.Logo),
+		ConsecutiveRoundLosses: t.Consecutive_round_losses,
+		TimeoutsRemaining:      t.Timeouts_remaining,
+		MatchesWonThisSeries:   t.Matches_won_this_series,
+		Side:                   t.Side,
+		Name:                   t.Name,
+		Country:                nullable(t.Country),
+		ID:                     nullable(t.Id),
+		Orientation:            t.Orientation,
+		Extra:                  extra(t.Extra),
+	}
+}
+
+// FromPlayer converts a player. Weapons are ordered by name and numbered
+// weapon_0, weapon_1, … as in the game payload.
+func FromPlayer(p *models.Player) *Player {
+	if p == nil {
+		return nil
+	}
+
+	out := &Player{
+		SteamID:      p.SteamId,
+		Name:         p.Name,
+		DefaultName:  p.DefaultName,
+		Clan:         p.Clan,
+		ObserverSlot: p.Observer_slot,
+		Team:         FromTeam(p.Team),
+		Weapons:      fromWeapons(p.Weapons),
+		Position:     p.Position[:],
+		Forward:      p.Forward[:],
+		Avatar:       nullable(p.Avatar),
+		Country:      nullable(p.Country),
+		RealName:     nullable(p.RealName),
+		Extra:        extra(p.Extra),
+	}
+	if s := p.State; s != nil {
+		out.State = &PlayerState{
+			Health:        s.Health,
+			Armor:         s.Armor,
+			Helmet:        s.Helmet,
+			DefuseKit:     s.DefuseKit,
+			Flashed:       s.Flashed,
+			Smoked:        s.Smoked,
+			Burning:       s.Burning,
+			Money:         s.Money,
+			RoundKills:    s.Round_kills,
+			RoundKillHS:   s.Round_killhs,
+			RoundTotalDmg: s.Round_totaldmg,
+			EquipValue:    s.Equip_value,
+			ADR:           s.Adr,
+		}
+	}
+	if s := p.Match_stats; s != nil {
+		out.Stats = &PlayerStats{Kills: s.Kills, Assists: s.Assists, Deaths: s.Deaths, MVPs: s.Mvps, Score: s.Score}
+	}
+	return out
+}
+
+// FromScore converts a RoundEnd or MatchEnd payload
+func FromScore(s *models.Score) *Score {
+	if s == nil {
+		return nil
+	}
+	return &Score{Winner: FromTeam(s.Winner), Loser: FromTeam(s.Loser), Map: FromMap(s.Map), MapEnd: s.MapEnd}
+}
+
+// FromKill converts a Kill payload; the weapon is reduced to its name
+func FromKill(k *models.KillEvent) *KillEvent {
+	if k == nil {
+		return nil
+	}
+	return &KillEvent{
+		Killer:        FromPlayer(k.Attacker),
+		Victim:        FromPlayer(k.Victim),
+		Assister:      FromPlayer(k.Assister),
+		Flashed:       k.Flashed,
+		Headshot:      k.Headshot,
+		Weapon:        weaponName(k.Weapon),
+		Wallbang:      k.Wallbang,
+		AttackerBlind: k.AttackerBlind,
+		ThruSmoke:     k.ThruSmoke,
+		NoScope:       k.NoScope,
+		AttackerInAir: k.AttackerInAir,
+	}
+}
+
+// FromHurt converts a Hurt payload; the weapon is reduced to its name
+func FromHurt(h *models.HurtEvent) *HurtEvent {
+	if h == nil {
+		return nil
+	}
+	return &HurtEvent{
+		Attacker:  FromPlayer(h.Attacker),
+		Victim:    FromPlayer(h.Victim),
+		Health:    h.Health,
+		Armor:     h.Armor,
+		Weapon:    weaponName(h.Weapon),
+		DmgHealth: h.DmgHealth,
+		DmgArmor:  h.DmgArmor,
+		HitGroup:  h.HitGroup,
+	}
+}
+
+func fromPlayers(players map[string]*models.Player) []*Player {
+	sorted := make([]*models.Player, 0, len(players))
+	for _, p := range players {
+		if p != nil {
+			sorted = append(sorted, p)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Observer_slot != sorted[j].Observer_slot {
+			return sorted[i].Observer_slot < sorted[j].Observer_slot
+		}
+		return sorted[i].SteamId < sorted[j].SteamId
+	})
+
+	out := make([]*Player, 0, len(sorted))
+	for _, p := range sorted {
+		out = append(out, FromPlayer(p))
+	}
+	return out
+}
+
+func fromWeapons(weapons map[string]*models.Weapon) []*Weapon {
+	names := make([]string, 0, len(weapons))
+	for name, w := range weapons {
+		if w != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := make([]*Weapon, 0, len(names))
+	for i, name := range names {
+		w := weapons[name]
+		out = append(out, &Weapon{
+			ID:          "weapon_" + strconv.Itoa(i),
+			Name:        w.Name,
+			PaintKit:    w.PaintKit,
+			Type:        w.Type,
+			AmmoClip:    w.Ammo_clip,
+			AmmoClipMax: w.Ammo_clip_max,
+			AmmoReserve: w.Ammo_reserve,
+			State:       w.State,
+		})
+	}
+	return out
+}
+
+func fromObserver(o *models.Observer) *Observer {
+	if o == nil {
+		return nil
+	}
+	return &Observer{Activity: o.Activity, SpecTarget: o.Spectarget, Position: o.Position[:], Forward: o.Forward[:]}
+}
+
+func fromBomb(b *models.Bomb) *Bomb {
+	if b == nil {
+		return nil
+	}
+	out := &Bomb{State: b.State, Countdown: b.Countdown, Player: FromPlayer(b.Player), Position: b.Position[:]}
+	if b.Site != "" {
+		site := b.Site
+		out.Site = &site
+	}
+	return out
+}
+
+func fromGrenades(grenades map[string]*models.Grenade) []*Grenade {
+	ids := make([]string, 0, len(grenades))
+	for id, g := range grenades {
+		if g != nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	out := make([]*Grenade, 0, len(ids))
+	for _, id := range ids {
+		g := grenades[id]
+		grenade := &Grenade{
+			ID:         id,
+			Owner:      g.Owner,
+			Type:       g.Type,
+			Lifetime:   g.Lifetime,
+			EffectTime: g.EffectTime,
+		}
+		if g.Type == models.GrenadeTypeIncendiary {
+			for i, flame := range g.Flames {
+				grenade.Flames = append(grenade.Flames, &Flame{ID: "flame_" + strconv.Itoa(i), Position: flame[:]})
+			}
+		} else {
+			grenade.Position = g.Position[:]
+			grenade.Velocity = g.Velocity[:]
+		}
+		out = append(out, grenade)
+	}
+	return out
+}
+
+func weaponName(w *models.Weapon) string {
+	if w == nil {
+		return ""
+	}
+	return w.Name
+}
+
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func extra(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
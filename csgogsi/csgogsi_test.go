@@ -0,0 +1,79 @@
+package csgogsi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+func TestFromState(t *testing.T) {
+	ct := &models.Team{Name: "CT", Side: models.CTSide, Consecutive_round_losses: 2}
+	killer := &models.Player{
+		SteamId:       "2",
+		Observer_slot: 2,
+		Team:          ct,
+		State:         &models.PlayerState{Health: 100, Round_killhs: 1},
+		Match_stats:   &models.PlayerMatchStats{Kills: 5},
+		Weapons: map[string]*models.Weapon{
+			"weapon_knife": {Name: "weapon_knife", Type: models.WeaponTypeKnife},
+			"weapon_ak47":  {Name: "weapon_ak47", Type: models.WeaponTypeRifle, State: models.WeaponStateActive},
+		},
+	}
+	victim := &models.Player{SteamId: "1", Observer_slot: 1, Team: ct}
+	state := &models.State{
+		Map:              &models.Map{Name: "de_mirage", Team_ct: ct},
+		AllPlayers:       map[string]*models.Player{"1": victim, "2": killer},
+		Bomb:             &models.Bomb{State: models.BombStateCarried},
+		Phase_countdowns: &models.PhaseCountdown{Phase: models.PhaseTypeLive, Phase_ends_in: 12.5},
+		Auth:             &models.Auth{Token: "secret"},
+	}
+
+	out := FromState(state)
+	if len(out.Players) != 2 || out.Players[0].SteamID != "1" || out.Players[1].SteamID != "2" {
+		t.Fatalf("players not ordered by observer slot: %+v", out.Players)
+	}
+	weapons := out.Players[1].Weapons
+	if len(weapons) != 2 || weapons[0].ID != "weapon_0" || weapons[0].Name != "weapon_ak47" {
+		t.Fatalf("weapons = %+v", weapons)
+	}
+	if out.Players[1].Stats.Kills != 5 || out.Map.TeamCT.ConsecutiveRoundLosses != 2 {
+		t.Fatalf("stats or team not converted")
+	}
+	if out.PhaseCountdowns.PhaseEndsIn != "12.5" {
+		t.Fatalf("phase_ends_in = %q", out.PhaseCountdowns.PhaseEndsIn)
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, want := range []string{`"round_killhs":1`, `"team_ct":{`, `"site":null`, `"logo":null`, `"auth":null`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON missing %s", want)
+		}
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Error("auth token leaked")
+	}
+
+	kill := Convert(&models.KillEvent{Attacker: killer, Victim: victim, Weapon: killer.Weapons["weapon_ak47"], Headshot: true}).(*KillEvent)
+	if kill.Killer.SteamID != "2" || kill.Weapon != "weapon_ak47" || !kill.Headshot {
+		t.Fatalf("kill = %+v", kill)
+	}
+}
+
+func TestEventName(t *testing.T) {
+	cases := map[models.Events]string{
+		models.DefuseEnd:     "defuseStop",
+		models.BombExploded:  "bombExplode",
+		models.Kill:          "kill",
+		models.BombPlantStop: "bombPlantStop",
+	}
+	for name, want := range cases {
+		if got := EventName(name); got != want {
+			t.Errorf("EventName(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
@@ -4,14 +4,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	models "github.com/nescabir/go-cs2-gsi/models"
 	rawModels "github.com/nescabir/go-cs2-gsi/raw"
 )
 
 // Digest parses a raw GSI JSON payload and updates internal state.
+// Calls are serialized, so Digest and DigestMIRV can be used from several
+// goroutines.
 func (gsi *CS2GSI) Digest(raw []byte) error {
-	publishRaw(raw)
+	gsi.digestMu.Lock()
+	defer gsi.digestMu.Unlock()
+
+	gsi.receivedAt = time.Now()
 
 	normalized := NormalizeGSIPayload(raw)
 	merged, err := gsi.payloadAcc.Merge(normalized)
@@ -21,8 +28,10 @@ func (gsi *CS2GSI) Digest(raw []byte) error {
 
 	stateRaw := &rawModels.State{}
 	if err := json.Unmarshal(merged, stateRaw); err != nil {
+		gsi.publishRaw(raw, nil)
 		return fmt.Errorf("failed to decode JSON: %w", err)
 	}
+	gsi.publishRaw(raw, payloadState(stateRaw))
 
 	if err := gsi.validateAuthToken(stateRaw); err != nil {
 		return err
@@ -31,9 +40,60 @@ func (gsi *CS2GSI) Digest(raw []byte) error {
 	return gsi.digest(stateRaw)
 }
 
-// Snapshot returns the last successfully parsed game state.
+// payloadState returns the parts of a decoded payload that stamp an event
+// envelope: the provider timestamp, the map and the round phase
+func payloadState(raw *rawModels.State) *models.State {
+	state := &models.State{}
+	if raw.Provider != nil {
+		state.Provider = &models.Provider{Timestamp: float32(raw.Provider.Timestamp)}
+	}
+	if raw.Map != nil {
+		state.Map = &models.Map{
+			Name:  raw.Map.Name,
+			Phase: parseMapPhase(string(raw.Map.Phase)),
+			Round: raw.Map.Round,
+		}
+	}
+	if raw.Round != nil {
+		state.Round = &models.Round{Phase: parseRoundPhase(string(raw.Round.Phase))}
+	}
+	return state
+}
+
+// Snapshot returns a deep copy of the last successfully parsed game state.
+// It is safe to call from any goroutine, including from event handlers.
 func (gsi *CS2GSI) Snapshot() *models.State {
-	return gsi.current
+	gsi.stateMu.RLock()
+	current := gsi.current
+	gsi.stateMu.RUnlock()
+
+	// States are never mutated once stored, so copying outside the lock is safe
+	return current.Clone()
+}
+
+// Reset forgets everything learned from previous payloads: the current and
+// last state, the damage history behind ADR, the delta accumulator and
+// whether MIRV kills were received. The next Digest behaves like the first
+// one after New. Subscriptions and the
+// event sequence are kept.
+func (gsi *CS2GSI) Reset() {
+	gsi.digestMu.Lock()
+	defer gsi.digestMu.Unlock()
+
+	gsi.damage = make([]models.RoundDamage, 0, 60)
+	gsi.players = make([]models.Player, 0, 16)
+	gsi.teams = &teams{}
+	gsi.payloadAcc = newPayloadAcc()
+	gsi.mirvKills = false
+	gsi.last = nil
+	gsi.setCurrent(nil)
+}
+
+// setCurrent stores the state returned by Snapshot
+func (gsi *CS2GSI) setCurrent(state *models.State) {
+	gsi.stateMu.Lock()
+	gsi.current = state
+	gsi.stateMu.Unlock()
 }
 
 func (gsi *CS2GSI) validateAuthToken(rawState *rawModels.State) error {
@@ -50,6 +110,23 @@ func (gsi *CS2GSI) validateAuthToken(rawState *rawModels.State) error {
 	return nil
 }
 
+// validateMIRVToken checks the token of a MIRV request. HLAE payloads have
+// no auth block, so it is sent in the X-Auth-Token header or the "token"
+// query parameter.
+func (gsi *CS2GSI) validateMIRVToken(r *http.Request) error {
+	if gsi.config.ExpectedToken == "" {
+		return nil
+	}
+	token := r.Header.Get("X-Auth-Token")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if token != gsi.config.ExpectedToken {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
 // IsInvalidToken reports whether err is an auth token validation failure.
 func IsInvalidToken(err error) bool {
 	return errors.Is(err, ErrInvalidToken)
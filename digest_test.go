@@ -1,6 +1,7 @@
 package cs2gsi
 
 import (
+	"errors"
 	"os"
 	"sync"
 	"testing"
@@ -39,6 +40,41 @@ func TestRawEventBeforeData(t *testing.T) {
 	}
 }
 
+func TestRawEventStampedFromPayload(t *testing.T) {
+	gsi := New(NewConfig())
+	var raws []Event[[]byte]
+	SubscribeTo(gsi, Raw, func(e Event[[]byte]) { raws = append(raws, e) })
+
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	first := gsi.Snapshot()
+	next := []byte(`{"provider":{"timestamp":1700000100},"map":{"name":"de_nuke","phase":"live","round":7},"round":{"phase":"over"}}`)
+	if err := gsi.Digest(next); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	if err := gsi.Digest([]byte(`{`)); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+
+	if len(raws) != 3 {
+		t.Fatalf("raw events = %d, want 3", len(raws))
+	}
+	if raws[0].Map != first.Map.Name || raws[0].ProviderTimestamp != first.Provider.Timestamp {
+		t.Fatalf("first raw envelope = %s/%v, want the fixture's %s/%v", raws[0].Map, raws[0].ProviderTimestamp, first.Map.Name, first.Provider.Timestamp)
+	}
+	if raws[1].Map != "de_nuke" || raws[1].Round != 7 || raws[1].ProviderTimestamp != 1700000100 {
+		t.Fatalf("second raw envelope = %s/%d/%v, want de_nuke/7/1700000100", raws[1].Map, raws[1].Round, raws[1].ProviderTimestamp)
+	}
+	if raws[2].Map != "" || raws[2].Round != 0 {
+		t.Fatalf("undecodable raw envelope = %s/%d, want empty", raws[2].Map, raws[2].Round)
+	}
+}
+
 func TestAuthTokenValidation(t *testing.T) {
 	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
 	if err != nil {
@@ -149,3 +185,69 @@ func TestDamageExportedOnState(t *testing.T) {
 		t.Fatal("expected damage history on state")
 	}
 }
+
+func TestSnapshotConcurrentWithDigest(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{Bus: NewBus()})
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	SubscribeTo(gsi, Data, func(e Event[*models.State]) {
+		if gsi.Snapshot() == nil {
+			t.Error("expected snapshot from inside a handler")
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if err := gsi.Digest(raw); err != nil {
+					t.Errorf("Digest: %v", err)
+					return
+				}
+				snap := gsi.Snapshot()
+				snap.Map.Team_ct.Score = -1
+			}
+		}()
+	}
+	wg.Wait()
+
+	if gsi.Snapshot().Map.Team_ct.Score == -1 {
+		t.Fatal("mutating a snapshot changed the stored state")
+	}
+}
+
+func TestReset(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{Bus: NewBus()})
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	gsi.Reset()
+	if gsi.Snapshot() != nil {
+		t.Fatal("expected no state after Reset")
+	}
+	if _, err := gsi.DigestMIRV([]byte(`{}`), MIRVEventPlayerDeath); !errors.Is(err, ErrMIRVNoPriorState) {
+		t.Fatalf("DigestMIRV after Reset error = %v, want ErrMIRVNoPriorState", err)
+	}
+
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest after Reset: %v", err)
+	}
+	if gsi.Snapshot() == nil {
+		t.Fatal("expected state after Digest")
+	}
+}
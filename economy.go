@@ -0,0 +1,274 @@
+package cs2gsi
+
+import (
+	"sort"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// primaryTypes are the weapon types counted as primaries
+var primaryTypes = map[models.WeaponType]bool{
+	models.WeaponTypeRifle:         true,
+	models.WeaponTypeSniperRifle:   true,
+	models.WeaponTypeSubmachineGun: true,
+	models.WeaponTypeShotgun:       true,
+	models.WeaponTypeMachineGun:    true,
+}
+
+// publishBuySummaries publishes the buy of each team, CT first
+func (gsi *CS2GSI) publishBuySummaries(state *models.State) {
+	if state.Map == nil {
+		return
+	}
+	for _, team := range []*models.Team{state.Map.Team_ct, state.Map.Team_t} {
+		if team == nil {
+			continue
+		}
+		summary := gsi.buySummary(state, team)
+		gsi.logger.Info("Buy detected", "team", team.Side, "type", summary.Type)
+		gsi.publishBuySummary(summary)
+	}
+}
+
+// buySummary aggregates the equipment of the players on team's side
+func (gsi *CS2GSI) buySummary(state *models.State, team *models.Team) *models.BuySummaryEvent {
+	summary := &models.BuySummaryEvent{Team: team, Primaries: []string{}}
+	for _, player := range state.AllPlayers {
+		if player.Team == nil || player.Team.Side != team.Side || player.State == nil {
+			continue
+		}
+		summary.Players++
+		summary.Money += player.State.Money
+		summary.EquipValue += player.State.Equip_value
+		if player.State.Armor > 0 {
+			summary.Armor++
+		}
+		if player.State.Helmet {
+			summary.Helmets++
+		}
+		if player.State.DefuseKit {
+			summary.DefuseKits++
+		}
+		for _, weapon := range player.Weapons {
+			if primaryTypes[weapon.Type] {
+				summary.Primaries = append(summary.Primaries, weapon.Name)
+			}
+		}
+	}
+	sort.Strings(summary.Primaries)
+	summary.Type = gsi.classifyBuy(state, summary)
+	return summary
+}
+
+// classifyBuy compares the average equipment value and money left per
+// player with Config.BuyThresholds. A full buy also needs most players
+// carrying a primary and armor, and a force most players carrying either,
+// so that utility and pistols alone do not count. The first round of each
+// regulation half is a pistol round whatever was bought.
+func (gsi *CS2GSI) classifyBuy(state *models.State, summary *models.BuySummaryEvent) models.BuyType {
+	if state.Map.Round == 0 || state.Map.Round == gsi.regulationMaxRounds {
+		return models.BuyTypePistol
+	}
+	if summary.Players == 0 {
+		return models.BuyTypeEco
+	}
+
+	thresholds := gsi.config.BuyThresholds
+	equip := summary.EquipValue / summary.Players
+	money := summary.Money / summary.Players
+	armed := 2*len(summary.Primaries) > summary.Players
+	armored := 2*summary.Armor > summary.Players
+	switch {
+	case equip < thresholds.Eco:
+		return models.BuyTypeEco
+	case equip >= thresholds.FullBuy && armed && armored:
+		return models.BuyTypeFullBuy
+	case money < thresholds.ForceMoney && (armed || armored):
+		return models.BuyTypeForce
+	default:
+		return models.BuyTypeHalfBuy
+	}
+}
+
+// Round rewards of CS2 competitive, per player
+const (
+	startMoney    = 800
+	maxMoney      = 16000
+	winReward     = 3250
+	bombWinReward = 3500
+	lossBonus     = 1400
+	lossBonusStep = 500
+	lossBonusTier = 5
+	plantBonus    = 800
+	killReward    = 300
+)
+
+// Kill rewards that differ from killReward, by weapon type and by weapon
+// name for the weapons paid differently from their type
+var (
+	killRewardsByType = map[models.WeaponType]int{
+		models.WeaponTypeKnife:         1500,
+		models.WeaponTypeSubmachineGun: 600,
+		models.WeaponTypeShotgun:       900,
+	}
+	killRewardsByName = map[string]int{
+		"weapon_awp":    100,
+		"weapon_cz75a":  100,
+		"weapon_p90":    300,
+		"weapon_xm1014": 600,
+	}
+)
+
+// applyEconomy sets the economy forecast of state. While the round is
+// played it is computed from the state; at round end it is computed from the
+// last state of the round and the outcome, then kept until the next round
+// since the money shown during the round end may already include rewards.
+func (gsi *CS2GSI) applyEconomy(state *models.State) {
+	last := gsi.last
+	switch {
+	case state.Map == nil || state.Round == nil || state.Map.Phase == models.MapPhaseWarmup:
+		state.Economy = nil
+	case isRoundEnd(last, state):
+		state.Economy = gsi.forecastEconomy(last, state)
+	case state.Round.Phase == models.RoundPhaseOver && last != nil && last.Economy != nil:
+		state.Economy = last.Economy
+	default:
+		state.Economy = gsi.forecastEconomy(state, nil)
+	}
+}
+
+// forecastEconomy forecasts the money after the round played in state. With
+// end set, the round is decided: the outcome is read from end and kills made
+// in end are paid according to the killer's weapon.
+func (gsi *CS2GSI) forecastEconomy(state, end *models.State) *models.Economy {
+	economy := &models.Economy{}
+	planted := state.Round.Bomb != ""
+	if end != nil {
+		round := currentRound(end)
+		for _, info := range end.Map.Rounds {
+			if info.Round == round {
+				economy.Outcome = info.Outcome
+			}
+		}
+		planted = end.Round.Bomb != ""
+	}
+
+	// Money is reset when the next round starts a half
+	reset := 0
+	played := state.Map.Round + 1
+	regulation, overtime := gsi.regulationMaxRounds, gsi.overtimeMaxRounds
+	if played == regulation {
+		reset = startMoney
+	} else if played >= 2*regulation && (played-2*regulation)%overtime == 0 {
+		reset = gsi.config.OvertimeStartMoney
+	}
+
+	for _, team := range []*models.Team{state.Map.Team_ct, state.Map.Team_t} {
+		if team == nil {
+			continue
+		}
+		tier := min(team.Consecutive_round_losses+1, lossBonusTier)
+		forecast := &models.EconomyForecast{
+			Team:          team,
+			LossBonusTier: tier,
+			LossBonus:     lossBonus + lossBonusStep*(tier-1),
+			Players:       []models.PlayerForecast{},
+		}
+
+		for _, player := range sortedPlayers(state.AllPlayers) {
+			if player.Team == nil || player.Team.Side != team.Side || player.State == nil {
+				continue
+			}
+			money := player.State.Money
+			loss := forecast.LossBonus
+			if team.Side == models.TSide && planted {
+				loss += plantBonus
+			}
+			p := models.PlayerForecast{
+				SteamId: player.SteamId,
+				Money:   money,
+				IfWin:   money + winReward,
+				IfLoss:  money + loss,
+				// From the top tiers the loss bonus can exceed the win reward
+				Minimum: money + min(winReward, loss),
+			}
+			// Terrorists alive when the time runs out get nothing
+			alive := player.State.Health > 0
+			if team.Side == models.TSide && !planted && alive {
+				p.Minimum = money
+			}
+
+			if end != nil {
+				if after, ok := end.AllPlayers[player.SteamId]; ok && after.State != nil {
+					alive = after.State.Health > 0
+					p.Expected = money + roundKillRewards(player, after)
+				}
+				switch {
+				case end.Round.Win_team == team.Side:
+					p.Expected += outcomeReward(economy.Outcome)
+				case economy.Outcome == models.CTWinTimeLimit && alive:
+				default:
+					p.Expected += loss
+				}
+			}
+
+			if reset > 0 {
+				p.Minimum, p.IfWin, p.IfLoss = reset, reset, reset
+				if end != nil {
+					p.Expected = reset
+				}
+			}
+			for _, money := range []*int{&p.Minimum, &p.IfWin, &p.IfLoss, &p.Expected} {
+				*money = min(*money, maxMoney)
+			}
+
+			if len(forecast.Players) == 0 || p.Minimum < forecast.MinimumMoney {
+				forecast.MinimumMoney = p.Minimum
+			}
+			forecast.Players = append(forecast.Players, p)
+		}
+
+		if team.Side == models.CTSide {
+			economy.CT = forecast
+		} else {
+			economy.T = forecast
+		}
+	}
+	return economy
+}
+
+// roundKillRewards is the money player earns for the kills made between
+// before and after, paid according to the weapon held
+func roundKillRewards(before, after *models.Player) int {
+	kills := after.State.Round_kills - before.State.Round_kills
+	if kills <= 0 {
+		return 0
+	}
+	weapon := activeWeapon(after)
+	if weapon == nil {
+		weapon = activeWeapon(before)
+	}
+	return kills * weaponKillReward(weapon)
+}
+
+// weaponKillReward is the money paid for a kill with weapon
+func weaponKillReward(weapon *models.Weapon) int {
+	if weapon == nil {
+		return killReward
+	}
+	if reward, ok := killRewardsByName[weapon.Name]; ok {
+		return reward
+	}
+	if reward, ok := killRewardsByType[weapon.Type]; ok {
+		return reward
+	}
+	return killReward
+}
+
+// outcomeReward is the win reward of outcome
+func outcomeReward(outcome models.RoundOutcome) int {
+	if outcome == models.CTWinDefuse || outcome == models.TWinBomb {
+		return bombWinReward
+	}
+	return winReward
+}
@@ -0,0 +1,27 @@
+package cs2gsi
+
+import (
+	"testing"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+func TestWeaponKillReward(t *testing.T) {
+	tests := []struct {
+		weapon *models.Weapon
+		want   int
+	}{
+		{&models.Weapon{Name: "weapon_knife", Type: models.WeaponTypeKnife}, 1500},
+		{&models.Weapon{Name: "weapon_mac10", Type: models.WeaponTypeSubmachineGun}, 600},
+		{&models.Weapon{Name: "weapon_p90", Type: models.WeaponTypeSubmachineGun}, 300},
+		{&models.Weapon{Name: "weapon_nova", Type: models.WeaponTypeShotgun}, 900},
+		{&models.Weapon{Name: "weapon_awp", Type: models.WeaponTypeSniperRifle}, 100},
+		{&models.Weapon{Name: "weapon_ak47", Type: models.WeaponTypeRifle}, 300},
+		{nil, 300},
+	}
+	for _, tt := range tests {
+		if got := weaponKillReward(tt.weapon); got != tt.want {
+			t.Errorf("weaponKillReward(%v) = %d, want %d", tt.weapon, got, tt.want)
+		}
+	}
+}
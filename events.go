@@ -1,215 +1,587 @@
 package cs2gsi
 
 import (
+	"context"
+	"fmt"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	models "github.com/nescabir/go-cs2-gsi/models"
 )
 
-// Event represents a typed event with a name and data
+// Event represents a typed event with a name, data and envelope metadata
 type Event[T any] struct {
-	Name string
-	Data T
+	Name string `json:"name"`
+	Data T      `json:"data"`
+	// Seq increases by one for every event published by an instance,
+	// across Digest and DigestMIRV
+	Seq uint64 `json:"seq"`
+	// Time is when the payload that produced the event was received
+	Time time.Time `json:"time"`
+	// ProviderTimestamp is Provider.Timestamp of the game state
+	ProviderTimestamp float32 `json:"providerTimestamp"`
+	// Map is the name of the map being played
+	Map string `json:"map"`
+	// Round is the round in progress, or the round just finished once it is over
+	Round int `json:"round"`
+}
+
+// GameEvent is the sum type over every event name in models.Events. Its
+// concrete types are the Event[T] instantiations used by the event names
+// below, e.g. Event[*models.KillEvent] for Kill; use a type switch or
+// EventName to tell them apart.
+type GameEvent interface {
+	EventName() string
+	sequence() uint64
+	envelope() Event[interface{}]
+}
+
+// EventName returns the name the event was published under
+func (e Event[T]) EventName() string {
+	return e.Name
+}
+
+func (e Event[T]) sequence() uint64 {
+	return e.Seq
+}
+
+// envelope returns the event with its payload as an interface value
+func (e Event[T]) envelope() Event[interface{}] {
+	return Event[interface{}]{
+		Name:              e.Name,
+		Data:              e.Data,
+		Seq:               e.Seq,
+		Time:              e.Time,
+		ProviderTimestamp: e.ProviderTimestamp,
+		Map:               e.Map,
+		Round:             e.Round,
+	}
 }
 
 // eventHandler represents a function that handles a specific event type
 type eventHandler[T any] func(event Event[T])
 
-// eventHandlers stores handlers for each event type
-var eventHandlers = make(map[string][]interface{})
-var handlersMutex sync.RWMutex
+// allEvents is the bus key for handlers subscribed to every event
+const allEvents = "*"
+
+// Bus dispatches published events to the handlers subscribed to it.
+// Each CS2GSI instance publishes to the bus set in Config.Bus, or to
+// DefaultBus when none is given.
+type Bus struct {
+	mu       sync.RWMutex
+	nextID   uint64
+	handlers map[string][]*subscriber
+	async    *AsyncOptions
+	dropped  atomic.Uint64
+}
+
+// OverflowPolicy decides what an asynchronous bus does when a subscriber's
+// queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the publisher wait until the queue has room
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued event to make room
+	OverflowDropOldest
+	// OverflowDropNewest discards the event being published
+	OverflowDropNewest
+)
+
+// AsyncOptions configures a bus created with NewAsyncBus
+type AsyncOptions struct {
+	QueueSize int
+	Overflow  OverflowPolicy
+}
+
+// subscriber is a single registered handler. On an asynchronous bus it owns
+// a bounded queue drained by its own goroutine.
+type subscriber struct {
+	id      uint64
+	handler interface{}
+	queue   chan func()
+	stop    chan struct{}
+	dropped atomic.Uint64
+}
+
+// NewBus creates an empty event bus that calls handlers synchronously
+// from the publishing goroutine
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]*subscriber)}
+}
+
+// NewAsyncBus creates an event bus where every subscriber receives events
+// through its own bounded queue and goroutine, so a slow handler does not
+// stall Digest. Events still queued when a handler unsubscribes are discarded.
+func NewAsyncBus(opts AsyncOptions) *Bus {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 64
+	}
+	return &Bus{
+		handlers: make(map[string][]*subscriber),
+		async:    &opts,
+	}
+}
+
+// Dropped returns the number of events discarded by this bus because a
+// subscriber queue was full
+func (b *Bus) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+// DefaultBus is the bus used by the package-level Subscribe functions and by
+// instances created without Config.Bus.
+var DefaultBus = NewBus()
+
+// EventSource is anything events can be subscribed on: a *Bus or a *CS2GSI.
+type EventSource interface {
+	Bus() *Bus
+}
 
-// eventName is a type-safe wrapper for event names
-type eventName[T any] string
+// Bus returns b itself so a *Bus can be used as an EventSource
+func (b *Bus) Bus() *Bus {
+	return b
+}
+
+// add registers handler under name and returns its subscription
+func (b *Bus) add(name string, handler interface{}) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	s := &subscriber{id: b.nextID, handler: handler}
+	if b.async != nil {
+		s.queue = make(chan func(), b.async.QueueSize)
+		s.stop = make(chan struct{})
+		go s.run()
+	}
+
+	b.handlers[name] = append(b.handlers[name], s)
+	return &Subscription{bus: b, name: name, sub: s, done: make(chan struct{})}
+}
+
+// run drains the subscriber queue until the subscriber is removed
+func (s *subscriber) run() {
+	for {
+		select {
+		case call := <-s.queue:
+			call()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// deliver hands call to the subscriber, either directly or through its
+// queue according to the bus overflow policy
+func (b *Bus) deliver(s *subscriber, call func()) {
+	if s.queue == nil {
+		call()
+		return
+	}
+
+	switch b.async.Overflow {
+	case OverflowDropNewest:
+		select {
+		case s.queue <- call:
+		default:
+			s.dropped.Add(1)
+			b.dropped.Add(1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.queue <- call:
+				return
+			default:
+			}
+			select {
+			case <-s.queue:
+				s.dropped.Add(1)
+				b.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.queue <- call:
+		case <-s.stop:
+		}
+	}
+}
+
+// remove unregisters a subscriber and stops its goroutine. The handler
+// slice is copied so that publishers iterating over the previous slice are
+// unaffected.
+func (b *Bus) remove(name string, sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub.stop != nil {
+		close(sub.stop)
+	}
+
+	current := b.handlers[name]
+	kept := make([]*subscriber, 0, len(current))
+	for _, s := range current {
+		if s != sub {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == 0 {
+		delete(b.handlers, name)
+		return
+	}
+	b.handlers[name] = kept
+}
+
+// Subscription is a handle to a registered event handler
+type Subscription struct {
+	bus  *Bus
+	name string
+	sub  *subscriber
+	once sync.Once
+	done chan struct{}
+}
+
+// Unsubscribe removes the handler from its bus. It is safe to call more
+// than once and from within the handler itself.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.bus.remove(s.name, s.sub)
+		close(s.done)
+	})
+}
+
+// Dropped returns the number of events discarded for this subscription
+// because its queue was full. It is always zero on a synchronous bus.
+func (s *Subscription) Dropped() uint64 {
+	return s.sub.dropped.Load()
+}
+
+// EventName is a type-safe event name: T is the payload type its handlers
+// receive. The predefined names below cover every event in models.Events.
+type EventName[T any] string
 
 // Event names with their associated types
 var (
-	Raw               eventName[[]byte]            = eventName[[]byte](string(models.Raw))
-	Data              eventName[*models.State]     = eventName[*models.State](string(models.Data))
-	RoundEnd          eventName[*models.Score]     = eventName[*models.Score](string(models.RoundEnd))
-	Kill              eventName[*models.KillEvent] = eventName[*models.KillEvent](string(models.Kill))
-	Hurt              eventName[*models.HurtEvent] = eventName[*models.HurtEvent](string(models.Hurt))
-	TimeoutStart      eventName[*models.Team]      = eventName[*models.Team](string(models.TimeoutStart))
-	TimeoutEnd        eventName[*models.Team]      = eventName[*models.Team](string(models.TimeoutEnd))
-	Mvp               eventName[*models.Player]    = eventName[*models.Player](string(models.Mvp))
-	FreezetimeStart   eventName[*models.Player]    = eventName[*models.Player](string(models.FreezetimeStart))
-	FreezetimeEnd     eventName[*models.Player]    = eventName[*models.Player](string(models.FreezetimeEnd))
-	IntermissionStart eventName[*models.Player]    = eventName[*models.Player](string(models.IntermissionStart))
-	IntermissionEnd   eventName[*models.Player]    = eventName[*models.Player](string(models.IntermissionEnd))
-	DefuseStart       eventName[*models.Player]    = eventName[*models.Player](string(models.DefuseStart))
-	DefuseEnd         eventName[*models.Player]    = eventName[*models.Player](string(models.DefuseEnd))
-	BombPlantStart    eventName[*models.Player]    = eventName[*models.Player](string(models.BombPlantStart))
-	BombPlantStop     eventName[*models.Player]    = eventName[*models.Player](string(models.BombPlantStop))
-	BombPlanted       eventName[*models.Player]    = eventName[*models.Player](string(models.BombPlanted))
-	BombDefused       eventName[*models.Player]    = eventName[*models.Player](string(models.BombDefused))
-	BombExploded      eventName[*models.Player]    = eventName[*models.Player](string(models.BombExploded))
-	MatchEnd          eventName[*models.Score]     = eventName[*models.Score](string(models.MatchEnd))
+	Raw               EventName[[]byte]                    = EventName[[]byte](string(models.Raw))
+	RawMIRV           EventName[*models.MIRVPayload]       = EventName[*models.MIRVPayload](string(models.RawMIRV))
+	Data              EventName[*models.State]             = EventName[*models.State](string(models.Data))
+	RoundEnd          EventName[*models.Score]             = EventName[*models.Score](string(models.RoundEnd))
+	Kill              EventName[*models.KillEvent]         = EventName[*models.KillEvent](string(models.Kill))
+	Hurt              EventName[*models.HurtEvent]         = EventName[*models.HurtEvent](string(models.Hurt))
+	Death             EventName[*models.Player]            = EventName[*models.Player](string(models.Death))
+	InferredKill      EventName[*models.InferredKillEvent] = EventName[*models.InferredKillEvent](string(models.InferredKill))
+	InferredHurt      EventName[*models.InferredHurtEvent] = EventName[*models.InferredHurtEvent](string(models.InferredHurt))
+	WeaponPurchase    EventName[*models.WeaponEvent]       = EventName[*models.WeaponEvent](string(models.WeaponPurchase))
+	WeaponPickup      EventName[*models.WeaponEvent]       = EventName[*models.WeaponEvent](string(models.WeaponPickup))
+	WeaponDrop        EventName[*models.WeaponEvent]       = EventName[*models.WeaponEvent](string(models.WeaponDrop))
+	WeaponSwitch      EventName[*models.WeaponEvent]       = EventName[*models.WeaponEvent](string(models.WeaponSwitch))
+	GrenadeThrow      EventName[*models.WeaponEvent]       = EventName[*models.WeaponEvent](string(models.GrenadeThrow))
+	BuySummary        EventName[*models.BuySummaryEvent]   = EventName[*models.BuySummaryEvent](string(models.BuySummary))
+	TimeoutStart      EventName[*models.Team]              = EventName[*models.Team](string(models.TimeoutStart))
+	TimeoutEnd        EventName[*models.Team]              = EventName[*models.Team](string(models.TimeoutEnd))
+	Mvp               EventName[*models.Player]            = EventName[*models.Player](string(models.Mvp))
+	FreezetimeStart   EventName[*models.Player]            = EventName[*models.Player](string(models.FreezetimeStart))
+	FreezetimeEnd     EventName[*models.Player]            = EventName[*models.Player](string(models.FreezetimeEnd))
+	IntermissionStart EventName[*models.Player]            = EventName[*models.Player](string(models.IntermissionStart))
+	IntermissionEnd   EventName[*models.Player]            = EventName[*models.Player](string(models.IntermissionEnd))
+	DefuseStart       EventName[*models.Player]            = EventName[*models.Player](string(models.DefuseStart))
+	DefuseEnd         EventName[*models.Player]            = EventName[*models.Player](string(models.DefuseEnd))
+	BombPlantStart    EventName[*models.Player]            = EventName[*models.Player](string(models.BombPlantStart))
+	BombPlantStop     EventName[*models.Player]            = EventName[*models.Player](string(models.BombPlantStop))
+	BombPlanted       EventName[*models.Player]            = EventName[*models.Player](string(models.BombPlanted))
+	BombDefused       EventName[*models.Player]            = EventName[*models.Player](string(models.BombDefused))
+	BombExploded      EventName[*models.Player]            = EventName[*models.Player](string(models.BombExploded))
+	MatchEnd          EventName[*models.Score]             = EventName[*models.Score](string(models.MatchEnd))
 )
 
-// Subscribe registers a handler for a specific event type
+// Subscribe registers a handler for a specific event type on DefaultBus
 // The type parameter T is automatically inferred from the event name
-func Subscribe[T any](eventName eventName[T], handler eventHandler[T]) {
-	handlersMutex.Lock()
-	defer handlersMutex.Unlock()
+func Subscribe[T any](eventName EventName[T], handler eventHandler[T]) *Subscription {
+	return SubscribeTo(DefaultBus, eventName, handler)
+}
 
-	eventHandlers[string(eventName)] = append(eventHandlers[string(eventName)], handler)
+// SubscribeTo registers a handler for a specific event type on the bus of src,
+// e.g. SubscribeTo(gsi, Kill, handler) to only receive events from gsi.
+func SubscribeTo[T any](src EventSource, eventName EventName[T], handler eventHandler[T]) *Subscription {
+	return src.Bus().add(string(eventName), handler)
 }
 
-// publish sends an event to all registered handlers
-func publish[T any](event Event[T]) {
-	handlersMutex.RLock()
-	handlers, found := eventHandlers[event.Name]
-	handlersMutex.RUnlock()
+// SubscribeAll registers a handler on DefaultBus that receives every event
+func SubscribeAll(handler func(event GameEvent)) *Subscription {
+	return SubscribeAllTo(DefaultBus, handler)
+}
 
-	if !found {
-		return
+// SubscribeAllTo registers a handler on the bus of src that receives every event
+func SubscribeAllTo(src EventSource, handler func(event GameEvent)) *Subscription {
+	return src.Bus().add(allEvents, handler)
+}
+
+// SubscribeOnce registers a handler on DefaultBus that is removed after
+// its first event
+func SubscribeOnce[T any](eventName EventName[T], handler eventHandler[T]) *Subscription {
+	return SubscribeOnceTo(DefaultBus, eventName, handler)
+}
+
+// SubscribeOnceTo registers a handler on the bus of src that is removed
+// after its first event
+func SubscribeOnceTo[T any](src EventSource, eventName EventName[T], handler eventHandler[T]) *Subscription {
+	var fired atomic.Bool
+	var sub *Subscription
+	ready := make(chan struct{})
+
+	sub = SubscribeTo(src, eventName, func(event Event[T]) {
+		if !fired.CompareAndSwap(false, true) {
+			return
+		}
+		<-ready
+		sub.Unsubscribe()
+		handler(event)
+	})
+	close(ready)
+
+	return sub
+}
+
+// SubscribeContext registers a handler on DefaultBus that is removed when
+// ctx is cancelled
+func SubscribeContext[T any](ctx context.Context, eventName EventName[T], handler eventHandler[T]) *Subscription {
+	return SubscribeContextTo(ctx, DefaultBus, eventName, handler)
+}
+
+// SubscribeContextTo registers a handler on the bus of src that is removed
+// when ctx is cancelled
+func SubscribeContextTo[T any](ctx context.Context, src EventSource, eventName EventName[T], handler eventHandler[T]) *Subscription {
+	sub := SubscribeTo(src, eventName, handler)
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.Unsubscribe()
+		case <-sub.done:
+		}
+	}()
+	return sub
+}
+
+// HandlerError describes a panic recovered from an event handler
+type HandlerError struct {
+	Event string
+	Seq   uint64
+	Panic interface{}
+	Stack []byte
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("handler for %q event panicked: %v", e.Event, e.Panic)
+}
+
+// guard wraps a handler call so that a panic is recovered and reported to
+// onError instead of unwinding through the publisher
+func guard(name string, seq uint64, onError func(*HandlerError), call func()) func() {
+	return func() {
+		defer func() {
+			if r := recover(); r != nil && onError != nil {
+				onError(&HandlerError{Event: name, Seq: seq, Panic: r, Stack: debug.Stack()})
+			}
+		}()
+		call()
 	}
+}
+
+// publish sends an event to all handlers registered on bus. Handler panics
+// are recovered and passed to onError so the remaining handlers still run.
+func publish[T any](bus *Bus, event Event[T], onError func(*HandlerError)) {
+	bus.mu.RLock()
+	handlers := bus.handlers[event.Name]
+	wildcards := bus.handlers[allEvents]
+	bus.mu.RUnlock()
 
-	for _, handler := range handlers {
+	for _, s := range handlers {
 		// Type assertion to call the handler with the correct type
-		if typedHandler, ok := handler.(eventHandler[T]); ok {
-			typedHandler(event)
+		if typedHandler, ok := s.handler.(eventHandler[T]); ok {
+			bus.deliver(s, guard(event.Name, event.Seq, onError, func() { typedHandler(event) }))
+		}
+	}
+
+	for _, s := range wildcards {
+		if handler, ok := s.handler.(func(GameEvent)); ok {
+			bus.deliver(s, guard(event.Name, event.Seq, onError, func() { handler(event) }))
 		}
 	}
 }
 
-// Helper functions for type-safe event publishing
-func publishRaw(data []byte) {
-	publish(Event[[]byte]{
-		Name: string(models.Raw),
-		Data: data,
-	})
+// publishEvent stamps the envelope metadata of the instance and publishes
+// the event on its bus
+func publishEvent[T any](gsi *CS2GSI, name models.Events, data T) {
+	publishEventFrom(gsi, gsi.current, name, data)
 }
 
-func publishData(data *models.State) {
-	publish(Event[*models.State]{
-		Name: string(models.Data),
+// publishEventFrom publishes an event whose envelope is stamped from state
+// instead of the current state
+func publishEventFrom[T any](gsi *CS2GSI, state *models.State, name models.Events, data T) {
+	event := Event[T]{
+		Name: string(name),
 		Data: data,
-	})
+		Seq:  gsi.seq.Add(1),
+		Time: gsi.receivedAt,
+	}
+	if state != nil {
+		if state.Provider != nil {
+			event.ProviderTimestamp = state.Provider.Timestamp
+		}
+		if state.Map != nil {
+			event.Map = state.Map.Name
+		}
+		event.Round = currentRound(state)
+	}
+	publish(gsi.bus, event, gsi.reportHandlerError)
 }
 
-func publishRoundEnd(data *models.Score) {
-	publish(Event[*models.Score]{
-		Name: string(models.RoundEnd),
-		Data: data,
-	})
+// reportHandlerError passes a recovered handler panic to Config.OnHandlerError,
+// or logs it when no hook is configured
+func (gsi *CS2GSI) reportHandlerError(err *HandlerError) {
+	if gsi.config.OnHandlerError != nil {
+		gsi.config.OnHandlerError(err)
+		return
+	}
+	gsi.logger.Error("event handler panicked", "event", err.Event, "seq", err.Seq, "panic", err.Panic, "stack", string(err.Stack))
 }
 
-func publishKill(data *models.KillEvent) {
-	publish(Event[*models.KillEvent]{
-		Name: string(models.Kill),
-		Data: data,
-	})
+// currentRound returns the round in progress, or the round just finished
+// while the round is over or the map has ended
+func currentRound(state *models.State) int {
+	if state.Map == nil {
+		return 0
+	}
+	if (state.Round != nil && state.Round.Phase == models.RoundPhaseOver) || state.Map.Phase == models.MapPhaseGameOver {
+		return state.Map.Round
+	}
+	return state.Map.Round + 1
 }
 
-func publishHurt(data *models.HurtEvent) {
-	publish(Event[*models.HurtEvent]{
-		Name: string(models.Hurt),
-		Data: data,
-	})
+// Helper functions for type-safe event publishing
+// publishRaw is stamped from the payload itself, which is not the current
+// state yet; state is nil when the payload could not be decoded
+func (gsi *CS2GSI) publishRaw(data []byte, state *models.State) {
+	publishEventFrom(gsi, state, models.Raw, data)
 }
 
-func publishTimeoutStart(data *models.Team) {
-	publish(Event[*models.Team]{
-		Name: string(models.TimeoutStart),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishRawMIRV(data *models.MIRVPayload) {
+	publishEvent(gsi, models.RawMIRV, data)
 }
 
-func publishTimeoutEnd(data *models.Team) {
-	publish(Event[*models.Team]{
-		Name: string(models.TimeoutEnd),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishData(data *models.State) {
+	publishEvent(gsi, models.Data, data)
 }
 
-func publishMvp(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.Mvp),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishRoundEnd(data *models.Score) {
+	publishEvent(gsi, models.RoundEnd, data)
 }
 
-func publishFreezetimeStart(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.FreezetimeStart),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishKill(data *models.KillEvent) {
+	publishEvent(gsi, models.Kill, data)
 }
 
-func publishFreezetimeEnd(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.FreezetimeEnd),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishHurt(data *models.HurtEvent) {
+	publishEvent(gsi, models.Hurt, data)
 }
 
-func publishIntermissionStart(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.IntermissionStart),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishDeath(data *models.Player) {
+	publishEvent(gsi, models.Death, data)
 }
 
-func publishIntermissionEnd(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.IntermissionEnd),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishInferredKill(data *models.InferredKillEvent) {
+	publishEvent(gsi, models.InferredKill, data)
 }
 
-func publishDefuseStart(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.DefuseStart),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishInferredHurt(data *models.InferredHurtEvent) {
+	publishEvent(gsi, models.InferredHurt, data)
 }
 
-func publishDefuseEnd(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.DefuseEnd),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishWeaponPurchase(data *models.WeaponEvent) {
+	publishEvent(gsi, models.WeaponPurchase, data)
 }
 
-func publishBombPlantStart(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.BombPlantStart),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishWeaponPickup(data *models.WeaponEvent) {
+	publishEvent(gsi, models.WeaponPickup, data)
 }
 
-func publishBombPlantStop(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.BombPlantStop),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishWeaponDrop(data *models.WeaponEvent) {
+	publishEvent(gsi, models.WeaponDrop, data)
 }
 
-func publishBombPlanted(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.BombPlanted),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishWeaponSwitch(data *models.WeaponEvent) {
+	publishEvent(gsi, models.WeaponSwitch, data)
 }
 
-func publishBombDefused(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.BombDefused),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishGrenadeThrow(data *models.WeaponEvent) {
+	publishEvent(gsi, models.GrenadeThrow, data)
 }
 
-func publishBombExploded(data *models.Player) {
-	publish(Event[*models.Player]{
-		Name: string(models.BombExploded),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishBuySummary(data *models.BuySummaryEvent) {
+	publishEvent(gsi, models.BuySummary, data)
 }
 
-func publishMatchEnd(data *models.Score) {
-	publish(Event[*models.Score]{
-		Name: string(models.MatchEnd),
-		Data: data,
-	})
+func (gsi *CS2GSI) publishTimeoutStart(data *models.Team) {
+	publishEvent(gsi, models.TimeoutStart, data)
+}
+
+func (gsi *CS2GSI) publishTimeoutEnd(data *models.Team) {
+	publishEvent(gsi, models.TimeoutEnd, data)
+}
+
+func (gsi *CS2GSI) publishMvp(data *models.Player) {
+	publishEvent(gsi, models.Mvp, data)
+}
+
+func (gsi *CS2GSI) publishFreezetimeStart(data *models.Player) {
+	publishEvent(gsi, models.FreezetimeStart, data)
+}
+
+func (gsi *CS2GSI) publishFreezetimeEnd(data *models.Player) {
+	publishEvent(gsi, models.FreezetimeEnd, data)
+}
+
+func (gsi *CS2GSI) publishIntermissionStart(data *models.Player) {
+	publishEvent(gsi, models.IntermissionStart, data)
+}
+
+func (gsi *CS2GSI) publishIntermissionEnd(data *models.Player) {
+	publishEvent(gsi, models.IntermissionEnd, data)
+}
+
+func (gsi *CS2GSI) publishDefuseStart(data *models.Player) {
+	publishEvent(gsi, models.DefuseStart, data)
+}
+
+func (gsi *CS2GSI) publishDefuseEnd(data *models.Player) {
+	publishEvent(gsi, models.DefuseEnd, data)
+}
+
+func (gsi *CS2GSI) publishBombPlantStart(data *models.Player) {
+	publishEvent(gsi, models.BombPlantStart, data)
+}
+
+func (gsi *CS2GSI) publishBombPlantStop(data *models.Player) {
+	publishEvent(gsi, models.BombPlantStop, data)
+}
+
+func (gsi *CS2GSI) publishBombPlanted(data *models.Player) {
+	publishEvent(gsi, models.BombPlanted, data)
+}
+
+func (gsi *CS2GSI) publishBombDefused(data *models.Player) {
+	publishEvent(gsi, models.BombDefused, data)
+}
+
+func (gsi *CS2GSI) publishBombExploded(data *models.Player) {
+	publishEvent(gsi, models.BombExploded, data)
+}
+
+func (gsi *CS2GSI) publishMatchEnd(data *models.Score) {
+	publishEvent(gsi, models.MatchEnd, data)
 }
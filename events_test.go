@@ -0,0 +1,303 @@
+package cs2gsi
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+func TestInstanceBusesAreIsolated(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	first := New(Config{Bus: NewBus()})
+	second := New(Config{Bus: NewBus()})
+
+	var firstCount, secondCount int
+	SubscribeTo(first, Data, func(e Event[*models.State]) { firstCount++ })
+	SubscribeTo(second, Data, func(e Event[*models.State]) { secondCount++ })
+
+	if err := first.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	if firstCount != 1 {
+		t.Fatalf("first instance data events = %d, want 1", firstCount)
+	}
+	if secondCount != 0 {
+		t.Fatalf("second instance data events = %d, want 0", secondCount)
+	}
+}
+
+func TestDefaultBusUsedWithoutConfigBus(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{})
+	var count int
+	sub := Subscribe(Data, func(e Event[*models.State]) { count++ })
+	defer sub.Unsubscribe()
+
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("data events on DefaultBus = %d, want 1", count)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	gsi := New(Config{Bus: NewBus()})
+
+	var count int
+	sub := SubscribeTo(gsi, Mvp, func(e Event[*models.Player]) { count++ })
+
+	gsi.publishMvp(nil)
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	gsi.publishMvp(nil)
+
+	if count != 1 {
+		t.Fatalf("handler calls = %d, want 1", count)
+	}
+}
+
+func TestSubscribeOnce(t *testing.T) {
+	gsi := New(Config{Bus: NewBus()})
+
+	var count int
+	SubscribeOnceTo(gsi, Mvp, func(e Event[*models.Player]) { count++ })
+
+	gsi.publishMvp(nil)
+	gsi.publishMvp(nil)
+
+	if count != 1 {
+		t.Fatalf("handler calls = %d, want 1", count)
+	}
+}
+
+func TestSubscribeContext(t *testing.T) {
+	gsi := New(Config{Bus: NewBus()})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var count int
+	sub := SubscribeContextTo(ctx, gsi, Mvp, func(e Event[*models.Player]) { count++ })
+
+	gsi.publishMvp(nil)
+	cancel()
+
+	select {
+	case <-sub.done:
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not removed after cancel")
+	}
+	gsi.publishMvp(nil)
+
+	if count != 1 {
+		t.Fatalf("handler calls = %d, want 1", count)
+	}
+}
+
+func TestAsyncBusOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  OverflowPolicy
+		wantLen int
+		wantTS  int
+	}{
+		{"drop newest", OverflowDropNewest, 2, 2},
+		{"drop oldest", OverflowDropOldest, 2, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gsi := New(Config{Bus: NewAsyncBus(AsyncOptions{QueueSize: 1, Overflow: tt.policy})})
+
+			started := make(chan struct{}, 4)
+			release := make(chan struct{})
+			received := make(chan int, 4)
+			sub := SubscribeTo(gsi, RoundEnd, func(e Event[*models.Score]) {
+				started <- struct{}{}
+				<-release
+				received <- e.Data.Winner.Score
+			})
+			defer sub.Unsubscribe()
+
+			publishScore := func(score int) {
+				gsi.publishRoundEnd(&models.Score{Winner: &models.Team{Score: score}})
+			}
+
+			publishScore(1)
+			<-started
+			for score := 2; score <= 4; score++ {
+				publishScore(score)
+			}
+			close(release)
+
+			var got []int
+			for len(got) < tt.wantLen {
+				select {
+				case score := <-received:
+					got = append(got, score)
+				case <-time.After(time.Second):
+					t.Fatalf("received %v, want %d events", got, tt.wantLen)
+				}
+			}
+
+			if got[0] != 1 || got[1] != tt.wantTS {
+				t.Fatalf("received %v, want [1 %d]", got, tt.wantTS)
+			}
+			if sub.Dropped() != 2 || gsi.Bus().Dropped() != 2 {
+				t.Fatalf("dropped = %d (bus %d), want 2", sub.Dropped(), gsi.Bus().Dropped())
+			}
+		})
+	}
+}
+
+func TestAsyncBusOverflowBlock(t *testing.T) {
+	gsi := New(Config{Bus: NewAsyncBus(AsyncOptions{QueueSize: 1, Overflow: OverflowBlock})})
+
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	received := make(chan int, 3)
+	sub := SubscribeTo(gsi, RoundEnd, func(e Event[*models.Score]) {
+		started <- struct{}{}
+		<-release
+		received <- e.Data.Winner.Score
+	})
+	defer sub.Unsubscribe()
+
+	publishScore := func(score int) {
+		gsi.publishRoundEnd(&models.Score{Winner: &models.Team{Score: score}})
+	}
+
+	publishScore(1)
+	<-started
+	publishScore(2)
+
+	// The queue is full, so the third event waits for the handler
+	published := make(chan struct{})
+	go func() {
+		publishScore(3)
+		close(published)
+	}()
+	select {
+	case <-published:
+		t.Fatal("publish returned while the queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("publish still blocked after the handler drained the queue")
+	}
+
+	var got []int
+	for len(got) < 3 {
+		select {
+		case score := <-received:
+			got = append(got, score)
+		case <-time.After(time.Second):
+			t.Fatalf("received %v, want 3 events", got)
+		}
+	}
+	if got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Fatalf("received %v, want [1 2 3]", got)
+	}
+	if sub.Dropped() != 0 || gsi.Bus().Dropped() != 0 {
+		t.Fatalf("dropped = %d (bus %d), want 0", sub.Dropped(), gsi.Bus().Dropped())
+	}
+}
+
+func TestEventEnvelopeMetadata(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	kill, err := os.ReadFile("testdata/mirv/player_death.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{Bus: NewBus()})
+	var events []GameEvent
+	SubscribeAllTo(gsi, func(e GameEvent) { events = append(events, e) })
+
+	before := time.Now()
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	seedLastState(gsi)
+	if _, err := gsi.DigestMIRV(kill, MIRVEventPlayerDeath); err != nil {
+		t.Fatalf("DigestMIRV: %v", err)
+	}
+
+	if len(events) != 4 {
+		t.Fatalf("events = %d, want 4 (raw, data, rawMirv, kill)", len(events))
+	}
+	data, ok := events[1].(Event[*models.State])
+	if !ok {
+		t.Fatalf("second event = %T, want data", events[1])
+	}
+	if data.Map != "de_dust2" || data.Round != 6 {
+		t.Fatalf("data map/round = %q/%d, want de_dust2/6", data.Map, data.Round)
+	}
+	if data.Time.Before(before) {
+		t.Fatalf("data time %v before digest start %v", data.Time, before)
+	}
+
+	if rawMIRV, ok := events[2].(Event[*models.MIRVPayload]); !ok || rawMIRV.Data.Type != MIRVEventPlayerDeath {
+		t.Fatalf("third event = %T, want rawMirv", events[2])
+	}
+	killEvent, ok := events[3].(Event[*models.KillEvent])
+	if !ok {
+		t.Fatalf("fourth event = %T, want kill", events[3])
+	}
+	if killEvent.Seq <= data.Seq || data.Seq <= events[0].(Event[[]byte]).Seq {
+		t.Fatalf("sequence not increasing: raw %d, data %d, kill %d",
+			events[0].(Event[[]byte]).Seq, data.Seq, killEvent.Seq)
+	}
+}
+
+func TestHandlerPanicIsolated(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	var reported []*HandlerError
+	gsi := New(Config{
+		Bus:            NewBus(),
+		OnHandlerError: func(err *HandlerError) { reported = append(reported, err) },
+	})
+
+	var delivered bool
+	SubscribeTo(gsi, Data, func(e Event[*models.State]) { panic("boom") })
+	SubscribeTo(gsi, Data, func(e Event[*models.State]) { delivered = true })
+
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	if !delivered {
+		t.Fatal("second handler was not called after the first panicked")
+	}
+	if gsi.Snapshot() == nil {
+		t.Fatal("expected state to be stored despite the panic")
+	}
+	if len(reported) != 1 {
+		t.Fatalf("reported errors = %d, want 1", len(reported))
+	}
+	if reported[0].Event != string(models.Data) || reported[0].Panic != "boom" || len(reported[0].Stack) == 0 {
+		t.Fatalf("unexpected handler error: %+v", reported[0])
+	}
+}
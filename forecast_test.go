@@ -0,0 +1,130 @@
+package cs2gsi_test
+
+import (
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	"github.com/nescabir/go-cs2-gsi/gsitest"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// economyRound is the round played after round rounds, with a CT who lost
+// the last two rounds against two Ts, everyone holding an MP9
+func economyRound(round int) *gsitest.Payload {
+	p := gsitest.NewPayload().Map("de_dust2", round).LossStreak(models.CTSide, 2)
+	for _, player := range []struct {
+		id    string
+		side  models.Side
+		money int
+	}{
+		{ct1, models.CTSide, 1000},
+		{t1, models.TSide, 15000},
+		{t2, models.TSide, 500},
+	} {
+		p.Player(player.id).Team(player.side).Money(player.money).
+			Weapon("weapon_mp9", models.WeaponTypeSubmachineGun, models.WeaponStateActive)
+	}
+	return p
+}
+
+// liveEconomy digests p and returns the forecast of the published state
+func liveEconomy(t *testing.T, gsi *cs2gsi.CS2GSI, p *gsitest.Payload) *models.Economy {
+	t.Helper()
+	rec := gsitest.NewRecorder(t, gsi)
+	p.Digest(t, gsi)
+	return gsitest.ExpectEvent(t, rec, cs2gsi.Data).Data.Economy
+}
+
+func forecastOf(economy *models.Economy, steamID string) models.PlayerForecast {
+	for _, forecast := range []*models.EconomyForecast{economy.CT, economy.T} {
+		for _, player := range forecast.Players {
+			if player.SteamId == steamID {
+				return player
+			}
+		}
+	}
+	return models.PlayerForecast{}
+}
+
+func TestEconomyForecast(t *testing.T) {
+	gsi, rec := newRecorded(t)
+	live := economyRound(5)
+
+	economy := liveEconomy(t, gsi, live)
+	if economy.CT.LossBonusTier != 3 || economy.CT.LossBonus != 2400 {
+		t.Fatalf("CT loss bonus = tier %d $%d, want tier 3 $2400", economy.CT.LossBonusTier, economy.CT.LossBonus)
+	}
+	want := models.PlayerForecast{SteamId: t1, Money: 15000, Minimum: 15000, IfWin: 16000, IfLoss: 16000}
+	if got := forecastOf(economy, t1); got != want {
+		t.Fatalf("t1 forecast = %+v, want %+v", got, want)
+	}
+	if economy.T.MinimumMoney != 500 {
+		t.Fatalf("T minimum = %d, want 500 for a T surviving the time limit", economy.T.MinimumMoney)
+	}
+
+	// The CT kills t2 with an SMG and wins when the time runs out
+	live.Clone().
+		Map("de_dust2", 6).RoundPhase(models.RoundPhaseOver).WinTeam(models.CTSide).RoundWin(6, models.CTWinTimeLimit).
+		Player(ct1).RoundKills(1, 0).
+		Player(t2).Health(0).
+		Digest(t, gsi)
+
+	economy = gsitest.ExpectEvent(t, rec, cs2gsi.RoundEnd).Data.Economy
+	if economy.Outcome != models.CTWinTimeLimit {
+		t.Fatalf("outcome = %q", economy.Outcome)
+	}
+	for id, money := range map[string]int{ct1: 1000 + 600 + 3250, t1: 15000, t2: 500 + 1400} {
+		if got := forecastOf(economy, id).Expected; got != money {
+			t.Errorf("%s expected = %d, want %d", id, got, money)
+		}
+	}
+}
+
+func TestEconomyForecastLossBonusAboveWinReward(t *testing.T) {
+	gsi, _ := newRecorded(t)
+	// Planted Ts at the fourth tier and CTs at the top tier lose more than a win pays
+	live := economyRound(9).LossStreak(models.CTSide, 4).LossStreak(models.TSide, 3).
+		RoundBomb(models.BombRoundStatePlanted)
+
+	economy := liveEconomy(t, gsi, live)
+	for id, want := range map[string]models.PlayerForecast{
+		ct1: {SteamId: ct1, Money: 1000, Minimum: 1000 + 3250, IfWin: 1000 + 3250, IfLoss: 1000 + 3400},
+		t2:  {SteamId: t2, Money: 500, Minimum: 500 + 3250, IfWin: 500 + 3250, IfLoss: 500 + 2900 + 800},
+	} {
+		if got := forecastOf(economy, id); got != want {
+			t.Errorf("%s forecast = %+v, want %+v", id, got, want)
+		}
+	}
+	if economy.CT.MinimumMoney != 4250 || economy.T.MinimumMoney != 3750 {
+		t.Fatalf("minimum money = CT %d T %d, want CT 4250 T 3750", economy.CT.MinimumMoney, economy.T.MinimumMoney)
+	}
+}
+
+func TestEconomyForecastHalftime(t *testing.T) {
+	for round, money := range map[int]int{11: 800, 23: 10000, 26: 10000} {
+		gsi := cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus(), RegulationMaxRounds: 12, OvertimeStartMoney: 10000})
+		economy := liveEconomy(t, gsi, economyRound(round))
+		if got := forecastOf(economy, t1); got.Minimum != money || got.IfWin != money {
+			t.Errorf("after round %d: forecast = %+v, want $%d", round+1, got, money)
+		}
+	}
+}
+
+func TestSnapshotEconomy(t *testing.T) {
+	gsi := cs2gsi.New(cs2gsi.NewConfig())
+	duel().Map("de_dust2", 3).Digest(t, gsi)
+
+	economy := gsi.Snapshot().Economy
+	if economy == nil || economy.CT == nil || economy.T == nil {
+		t.Fatalf("snapshot economy = %+v, want both sides forecast", economy)
+	}
+	if len(economy.CT.Players) != 1 || economy.CT.Players[0].SteamId != ct1 {
+		t.Fatalf("CT forecast players = %+v, want %s", economy.CT.Players, ct1)
+	}
+
+	// The snapshot is a copy
+	economy.CT.Players[0].IfWin = 0
+	if gsi.Snapshot().Economy.CT.Players[0].IfWin == 0 {
+		t.Fatal("mutating a snapshot changed the stored forecast")
+	}
+}
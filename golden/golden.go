@@ -0,0 +1,323 @@
+// Package golden checks event detection against whole recorded matches.
+//
+// A case is a directory holding capture files (*.ndjson, see the capture
+// package) and an events.golden file. The captures are replayed in name
+// order through a fresh CS2GSI and every published event is summarized on
+// one line; the lines must match events.golden. Set Options.Update to
+// rewrite the golden files after an intended change.
+package golden
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	"github.com/nescabir/go-cs2-gsi/capture"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// FileName is the name of the expected events file of a case
+const FileName = "events.golden"
+
+// Options configures how a case is replayed and summarized
+type Options struct {
+	// Config configures the instance the captures are replayed through. Its
+	// Bus is replaced by a private one.
+	Config cs2gsi.Config
+	// Include selects the events written to the golden file. Defaults to
+	// every event except Raw, RawMIRV and Data.
+	Include func(name string) bool
+	// Describe summarizes an event on one line. Defaults to Describe.
+	Describe func(event cs2gsi.GameEvent) string
+	// Update rewrites the golden files instead of comparing with them,
+	// typically set from a test flag
+	Update bool
+}
+
+func (o *Options) setDefaults() {
+	if o.Include == nil {
+		o.Include = func(name string) bool {
+			switch models.Events(name) {
+			case models.Raw, models.RawMIRV, models.Data:
+				return false
+			}
+			return true
+		}
+	}
+	if o.Describe == nil {
+		o.Describe = Describe
+	}
+}
+
+// RunAll runs every case directory below root as a subtest
+func RunAll(t *testing.T, root string, opts Options) {
+	t.Helper()
+
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("read golden cases: %v", err)
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		path := filepath.Join(root, dir.Name())
+		t.Run(dir.Name(), func(t *testing.T) {
+			Run(t, path, opts)
+		})
+	}
+}
+
+// Run replays the case in dir and compares its events with the golden file,
+// or rewrites the golden file when Options.Update is set
+func Run(t testing.TB, dir string, opts Options) {
+	t.Helper()
+
+	got, err := Events(dir, opts)
+	if err != nil {
+		t.Fatalf("replay %s: %v", dir, err)
+	}
+
+	path := filepath.Join(dir, FileName)
+	if opts.Update {
+		if err := os.WriteFile(path, []byte(strings.Join(got, "\n")+"\n"), 0o644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (set Options.Update to create it): %v", err)
+	}
+	want := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(want) == 1 && want[0] == "" {
+		want = nil
+	}
+
+	if diff := Diff(want, got); diff != "" {
+		t.Errorf("events differ from %s (-want +got):\n%s", path, diff)
+	}
+}
+
+// Events replays the captures in dir through a fresh instance and returns
+// the summary line of each included event. Payloads rejected by Digest or
+// DigestMIRV produce an error line, so rejections are checked too.
+func Events(dir string, opts Options) ([]string, error) {
+	opts.setDefaults()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.ndjson"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no capture files")
+	}
+	sort.Strings(files)
+
+	var entries []capture.Entry
+	for _, file := range files {
+		fileEntries, err := capture.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	config := opts.Config
+	config.Bus = cs2gsi.NewBus()
+	gsi := cs2gsi.New(config)
+
+	var lines []string
+	cs2gsi.SubscribeAllTo(gsi, func(event cs2gsi.GameEvent) {
+		if opts.Include(event.EventName()) {
+			lines = append(lines, opts.Describe(event))
+		}
+	})
+
+	replay := capture.NewReplayer(gsi, entries, capture.ReplayOptions{
+		OnError: func(entry capture.Entry, err error) {
+			lines = append(lines, fmt.Sprintf("error source=%s %v", entry.Source, err))
+		},
+	})
+	if err := replay.Run(context.Background()); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// Describe summarizes an event as its round, name and key fields, e.g.
+//
+//	round=6 roundEnd winner=T:Red loser=CT:Blue score=3-3 mapEnd=false
+//
+// Payload types without a dedicated summary are written as compact JSON.
+func Describe(event cs2gsi.GameEvent) string {
+	var round int
+	var fields string
+
+	switch e := event.(type) {
+	case cs2gsi.Event[*models.State]:
+		round = e.Round
+		fields = describeState(e.Data)
+	case cs2gsi.Event[*models.Score]:
+		round = e.Round
+		if e.Data != nil {
+			fields = fmt.Sprintf("winner=%s loser=%s score=%s mapEnd=%t",
+				team(e.Data.Winner), team(e.Data.Loser), score(e.Data.Winner, e.Data.Loser), e.Data.MapEnd)
+		}
+	case cs2gsi.Event[*models.Player]:
+		round = e.Round
+		fields = "player=" + player(e.Data)
+	case cs2gsi.Event[*models.Team]:
+		round = e.Round
+		fields = "team=" + team(e.Data)
+	case cs2gsi.Event[*models.KillEvent]:
+		round = e.Round
+		if k := e.Data; k != nil {
+			fields = fmt.Sprintf("attacker=%s victim=%s assister=%s weapon=%s headshot=%t",
+				player(k.Attacker), player(k.Victim), player(k.Assister), weapon(k.Weapon), k.Headshot)
+		}
+	case cs2gsi.Event[*models.HurtEvent]:
+		round = e.Round
+		if h := e.Data; h != nil {
+			fields = fmt.Sprintf("attacker=%s victim=%s weapon=%s health=%d dmgHealth=%d dmgArmor=%d",
+				player(h.Attacker), player(h.Victim), weapon(h.Weapon), h.Health, h.DmgHealth, h.DmgArmor)
+		}
+	case cs2gsi.Event[*models.InferredKillEvent]:
+		round = e.Round
+		if k := e.Data; k != nil {
+			fields = fmt.Sprintf("attacker=%s victim=%s weapon=%s headshot=%t confidence=%.2f",
+				player(k.Attacker), player(k.Victim), weapon(k.Weapon), k.Headshot, k.Confidence)
+		}
+	case cs2gsi.Event[*models.InferredHurtEvent]:
+		round = e.Round
+		if h := e.Data; h != nil {
+			fields = fmt.Sprintf("attacker=%s victim=%s weapon=%s health=%d dmgHealth=%d dmgArmor=%d confidence=%.2f",
+				player(h.Attacker), player(h.Victim), weapon(h.Weapon), h.Health, h.DmgHealth, h.DmgArmor, h.Confidence)
+		}
+	case cs2gsi.Event[*models.WeaponEvent]:
+		round = e.Round
+		if w := e.Data; w != nil {
+			fields = fmt.Sprintf("player=%s weapon=%s", player(w.Player), weapon(w.Weapon))
+			if w.Previous != nil {
+				fields += " previous=" + weapon(w.Previous)
+			}
+		}
+	case cs2gsi.Event[*models.BuySummaryEvent]:
+		round = e.Round
+		if b := e.Data; b != nil {
+			fields = fmt.Sprintf("team=%s type=%s players=%d money=%d equipValue=%d primaries=%d",
+				team(b.Team), b.Type, b.Players, b.Money, b.EquipValue, len(b.Primaries))
+		}
+	default:
+		var envelope struct {
+			Round int             `json:"round"`
+			Data  json.RawMessage `json:"data"`
+		}
+		data, err := json.Marshal(event)
+		if err == nil {
+			err = json.Unmarshal(data, &envelope)
+		}
+		if err != nil {
+			fields = "error=" + err.Error()
+		} else {
+			round = envelope.Round
+			fields = "data=" + string(envelope.Data)
+		}
+	}
+
+	line := fmt.Sprintf("round=%d %s", round, event.EventName())
+	if fields != "" {
+		line += " " + fields
+	}
+	return line
+}
+
+func describeState(state *models.State) string {
+	if state == nil || state.Map == nil {
+		return ""
+	}
+	fields := fmt.Sprintf("map=%s phase=%s score=%s", state.Map.Name, state.Map.Phase, score(state.Map.Team_ct, state.Map.Team_t))
+	if state.Round != nil {
+		fields += " roundPhase=" + string(state.Round.Phase)
+	}
+	return fields
+}
+
+func player(p *models.Player) string {
+	if p == nil {
+		return "-"
+	}
+	return p.SteamId
+}
+
+func team(t *models.Team) string {
+	if t == nil {
+		return "-"
+	}
+	return string(t.Side) + ":" + t.Name
+}
+
+func score(a, b *models.Team) string {
+	if a == nil || b == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d-%d", a.Score, b.Score)
+}
+
+func weapon(w *models.Weapon) string {
+	if w == nil {
+		return "-"
+	}
+	return w.Name
+}
+
+// Diff returns a line diff of want and got, prefixing removed lines with
+// "-" and added lines with "+", or an empty string when they are equal
+func Diff(want, got []string) string {
+	// Longest common subsequence table
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var b strings.Builder
+	changed := false
+	i, j := 0, 0
+	for i < len(want) || j < len(got) {
+		switch {
+		case i < len(want) && j < len(got) && want[i] == got[j]:
+			b.WriteString("  " + want[i] + "\n")
+			i++
+			j++
+		case j < len(got) && (i == len(want) || lcs[i][j+1] >= lcs[i+1][j]):
+			b.WriteString("+ " + got[j] + "\n")
+			changed = true
+			j++
+		default:
+			b.WriteString("- " + want[i] + "\n")
+			changed = true
+			i++
+		}
+	}
+	if !changed {
+		return ""
+	}
+	return b.String()
+}
@@ -0,0 +1,26 @@
+package golden
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("golden.update", false, "rewrite golden event files")
+
+func TestGoldenCases(t *testing.T) {
+	RunAll(t, "../testdata/golden", Options{Update: *update})
+}
+
+func TestDiff(t *testing.T) {
+	if diff := Diff([]string{"a", "b"}, []string{"a", "b"}); diff != "" {
+		t.Fatalf("equal inputs diff = %q", diff)
+	}
+
+	diff := Diff([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	for _, want := range []string{"- b\n", "+ x\n", "+ d\n", "  a\n"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package gsitest
+
+import (
+	"encoding/json"
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	models "github.com/nescabir/go-cs2-gsi/models"
+	rawModels "github.com/nescabir/go-cs2-gsi/raw"
+)
+
+const (
+	alice = "76561198000000001"
+	bob   = "76561198000000002"
+)
+
+func newInstance() *cs2gsi.CS2GSI {
+	return cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus()})
+}
+
+func twoPlayers() *Payload {
+	return NewPayload().
+		Map("de_mirage", 3).
+		Player(alice).Name("Alice").Team(models.CTSide).
+		Player(bob).Name("Bob").Team(models.TSide).
+		Weapon("weapon_c4", models.WeaponTypeC4, models.WeaponStateHolstered).
+		BombCarrier(bob)
+}
+
+func TestBombPlantedEvent(t *testing.T) {
+	gsi := newInstance()
+	rec := NewRecorder(t, gsi)
+
+	planting := twoPlayers().Bomb(models.BombStatePlanting)
+	planting.Digest(t, gsi)
+	planted := planting.Clone().Bomb(models.BombStatePlanted).RoundBomb(models.BombRoundStatePlanted)
+	planted.Digest(t, gsi)
+
+	event := ExpectEvent(t, rec, cs2gsi.BombPlanted)
+	if event.Data == nil || event.Data.SteamId != bob {
+		t.Fatalf("planter = %+v, want %s", event.Data, bob)
+	}
+	if event.Map != "de_mirage" || event.Round != 4 {
+		t.Fatalf("event map/round = %s/%d, want de_mirage/4", event.Map, event.Round)
+	}
+	ExpectNoEvent(t, rec, cs2gsi.BombDefused)
+	ExpectNames(t, rec, string(models.Data), string(models.BombPlanted), string(models.Data))
+}
+
+func TestRecorderReset(t *testing.T) {
+	gsi := newInstance()
+	rec := NewRecorder(t, gsi)
+
+	twoPlayers().Digest(t, gsi)
+	if len(rec.Events()) == 0 {
+		t.Fatal("expected recorded events")
+	}
+	rec.Reset()
+	if n := len(rec.Events()); n != 0 {
+		t.Fatalf("recorded %d events after Reset", n)
+	}
+}
+
+func TestPayloadJSON(t *testing.T) {
+	payload := twoPlayers().
+		Player(alice).Health(42).Armor(100).Helmet(true).Money(3100).
+		Weapon("weapon_ak47", models.WeaponTypeRifle, models.WeaponStateActive).
+		Weapon("weapon_ak47", models.WeaponTypeRifle, models.WeaponStateHolstered).
+		Weapon("weapon_knife", models.WeaponTypeKnife, models.WeaponStateActive).
+		Countdown(models.PhaseTypeFreezetime, 12.5).
+		Score(2, 1).
+		Token("secret")
+
+	var state rawModels.State
+	if err := json.Unmarshal(payload.JSON(), &state); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+
+	a := state.AllPlayers[alice]
+	if a == nil || a.State.Health != 42 || !a.State.Helmet || a.State.Money != 3100 {
+		t.Fatalf("alice = %+v", a)
+	}
+	if len(a.Weapons) != 2 || a.Weapons["weapon_0"].State != rawModels.WeaponStateHolstered || a.Weapons["weapon_1"].Name != "weapon_knife" {
+		t.Fatalf("alice weapons = %+v", a.Weapons)
+	}
+	if state.Player == nil || state.Player.Steamid != alice {
+		t.Fatalf("observed player = %+v, want %s", state.Player, alice)
+	}
+	if state.Phase_countdowns.Phase_ends_in != "12.5" || state.Map.Team_ct.Score != 2 || state.Auth.Token != "secret" {
+		t.Fatalf("countdown/score/token = %+v %d %+v", state.Phase_countdowns, state.Map.Team_ct.Score, state.Auth)
+	}
+}
+
+func TestPayloadCloneIsIndependent(t *testing.T) {
+	original := twoPlayers()
+	clone := original.Clone().Player(bob).Health(0).DropWeapon("weapon_c4")
+
+	var state rawModels.State
+	if err := json.Unmarshal(original.JSON(), &state); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if b := state.AllPlayers[bob]; b.State.Health != 100 || len(b.Weapons) != 1 {
+		t.Fatalf("original bob changed by clone: %+v", b)
+	}
+	if got := clone.Players(); len(got) != 2 || got[0] != alice {
+		t.Fatalf("clone players = %v", got)
+	}
+}
+
+func TestPlayerSetterWithoutPlayerPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	NewPayload().Health(10)
+}
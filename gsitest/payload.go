@@ -0,0 +1,426 @@
+// Package gsitest helps testing code built on cs2gsi: a fluent builder for
+// GSI payloads and a recorder with assertions on the published events.
+package gsitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	models "github.com/nescabir/go-cs2-gsi/models"
+	rawModels "github.com/nescabir/go-cs2-gsi/raw"
+)
+
+// Payload builds a complete GSI payload, as sent by CS2 with every data
+// feed enabled. Setters return the payload so calls can be chained:
+//
+//	gsitest.NewPayload().
+//		Map("de_mirage", 4).
+//		Player("76561198000000001").Team(models.TSide).Health(0).
+//		Bomb(models.BombStatePlanted)
+//
+// Player selects the player the player setters (Name, Team, Health, Money,
+// Weapon, …) apply to, creating it on first use.
+type Payload struct {
+	state    *rawModels.State
+	current  string
+	observed string
+	order    []string
+}
+
+// NewPayload returns a live round on de_dust2 with no players, the bomb
+// carried and empty grenades
+func NewPayload() *Payload {
+	return &Payload{
+		state: &rawModels.State{
+			Provider: &rawModels.Provider{Name: "Counter-Strike 2", AppId: 730},
+			Map: &rawModels.Map{
+				Mode:       "competitive",
+				Name:       "de_dust2",
+				Phase:      rawModels.MapPhaseLive,
+				Team_ct:    &rawModels.Team{Name: "CT", Timeouts_remaining: 1},
+				Team_t:     &rawModels.Team{Name: "T", Timeouts_remaining: 1},
+				Round_wins: map[string]rawModels.RoundOutcome{},
+			},
+			Round:            &rawModels.Round{Phase: rawModels.RoundPhaseLive},
+			Phase_countdowns: &rawModels.PhaseCountdown{Phase: rawModels.PhaseTypeLive, Phase_ends_in: "115.0"},
+			Bomb:             &rawModels.Bomb{State: rawModels.BombStateCarried, Countdown: "0", Position: "0, 0, 0"},
+			Grenades:         map[string]*rawModels.Grenade{},
+			AllPlayers:       map[string]*rawModels.Player{},
+			Auth:             &rawModels.Auth{},
+		},
+	}
+}
+
+// Clone returns an independent copy, handy for building the next tick
+func (p *Payload) Clone() *Payload {
+	state := &rawModels.State{}
+	if err := json.Unmarshal(p.JSON(), state); err != nil {
+		panic(fmt.Sprintf("gsitest: clone payload: %v", err))
+	}
+	return &Payload{
+		state:    state,
+		current:  p.current,
+		observed: p.observed,
+		order:    append([]string(nil), p.order...),
+	}
+}
+
+// JSON returns the payload as sent by the game
+func (p *Payload) JSON() []byte {
+	state := *p.state
+	if id := p.observedID(); id != "" {
+		state.Player = &rawModels.PlayerObserved{Player: *p.state.AllPlayers[id]}
+	}
+	data, err := json.Marshal(&state)
+	if err != nil {
+		panic(fmt.Sprintf("gsitest: encode payload: %v", err))
+	}
+	return data
+}
+
+// Digest feeds the payload to gsi, failing the test on error
+func (p *Payload) Digest(t testing.TB, gsi *cs2gsi.CS2GSI) {
+	t.Helper()
+	if err := gsi.Digest(p.JSON()); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+}
+
+// observedID is the player in the player block: the one set with Observe,
+// or the first player added
+func (p *Payload) observedID() string {
+	if p.observed != "" {
+		return p.observed
+	}
+	if len(p.order) > 0 {
+		return p.order[0]
+	}
+	return ""
+}
+
+// Map sets the map name and the number of completed rounds
+func (p *Payload) Map(name string, round int) *Payload {
+	p.state.Map.Name = name
+	p.state.Map.Round = round
+	return p
+}
+
+// MapPhase sets the map phase (warmup, live, intermission, gameover)
+func (p *Payload) MapPhase(phase models.MapPhase) *Payload {
+	p.state.Map.Phase = rawModels.MapPhase(phase)
+	return p
+}
+
+// RoundPhase sets the round phase (freezetime, live, over)
+func (p *Payload) RoundPhase(phase models.RoundPhase) *Payload {
+	p.state.Round.Phase = rawModels.RoundPhase(phase)
+	return p
+}
+
+// WinTeam sets the winner of the round being over
+func (p *Payload) WinTeam(side models.Side) *Payload {
+	p.state.Round.Win_team = rawModels.Side(side)
+	return p
+}
+
+// RoundBomb sets the bomb outcome of the round (planted, exploded, defused)
+func (p *Payload) RoundBomb(state models.BombRoundState) *Payload {
+	p.state.Round.Bomb = rawModels.BombRoundState(state)
+	return p
+}
+
+// RoundWin records the outcome of a completed round, numbered from 1
+func (p *Payload) RoundWin(round int, outcome models.RoundOutcome) *Payload {
+	p.state.Map.Round_wins[strconv.Itoa(round)] = rawModels.RoundOutcome(outcome)
+	return p
+}
+
+// Countdown sets the phase countdown
+func (p *Payload) Countdown(phase models.PhaseType, endsIn float32) *Payload {
+	p.state.Phase_countdowns.Phase = rawModels.PhaseType(phase)
+	p.state.Phase_countdowns.Phase_ends_in = strconv.FormatFloat(float64(endsIn), 'f', 1, 32)
+	return p
+}
+
+// Score sets the score of both teams
+func (p *Payload) Score(ct, t int) *Payload {
+	p.state.Map.Team_ct.Score = ct
+	p.state.Map.Team_t.Score = t
+	return p
+}
+
+// TeamName sets the name of the team playing a side
+func (p *Payload) TeamName(side models.Side, name string) *Payload {
+	p.team(side).Name = name
+	return p
+}
+
+// LossStreak sets the consecutive round losses of the team playing a side
+func (p *Payload) LossStreak(side models.Side, losses int) *Payload {
+	p.team(side).Consecutive_round_losses = losses
+	return p
+}
+
+func (p *Payload) team(side models.Side) *rawModels.Team {
+	if side == models.TSide {
+		return p.state.Map.Team_t
+	}
+	return p.state.Map.Team_ct
+}
+
+// Timestamp sets Provider.Timestamp
+func (p *Payload) Timestamp(ts float32) *Payload {
+	p.state.Provider.Timestamp = ts
+	return p
+}
+
+// Token sets the auth token
+func (p *Payload) Token(token string) *Payload {
+	p.state.Auth.Token = token
+	return p
+}
+
+// Bomb sets the bomb state
+func (p *Payload) Bomb(state models.BombState) *Payload {
+	p.state.Bomb.State = rawModels.BombState(state)
+	return p
+}
+
+// BombCarrier sets the player carrying, planting or defusing the bomb; an
+// empty steam id clears it
+func (p *Payload) BombCarrier(steamID string) *Payload {
+	p.state.Bomb.Player = steamID
+	return p
+}
+
+// BombCountdown sets the bomb timer
+func (p *Payload) BombCountdown(seconds float32) *Payload {
+	p.state.Bomb.Countdown = strconv.FormatFloat(float64(seconds), 'f', 1, 32)
+	return p
+}
+
+// Grenade adds or replaces a grenade in flight or in effect
+func (p *Payload) Grenade(id, owner string, grenadeType models.GrenadeType, position [3]float32) *Payload {
+	p.state.Grenades[id] = &rawModels.Grenade{
+		Owner:    owner,
+		Type:     rawModels.GrenadeType(grenadeType),
+		Position: vector(position),
+		Velocity: "0, 0, 0",
+		Lifetime: "0.0",
+	}
+	return p
+}
+
+// RemoveGrenade removes a grenade
+func (p *Payload) RemoveGrenade(id string) *Payload {
+	delete(p.state.Grenades, id)
+	return p
+}
+
+// Player selects the player the following player setters apply to. A new
+// player joins the CT side with full health, no armor and $800.
+func (p *Payload) Player(steamID string) *Payload {
+	if _, ok := p.state.AllPlayers[steamID]; !ok {
+		slot := len(p.order)
+		p.state.AllPlayers[steamID] = &rawModels.Player{
+			Steamid:       steamID,
+			Name:          "Player" + strconv.Itoa(slot+1),
+			Observer_slot: slot,
+			Team:          rawModels.CTSide,
+			Activity:      rawModels.PlayerActivityActive,
+			State:         &rawModels.PlayerState{Health: 100, Money: 800},
+			Weapons:       map[string]*rawModels.Weapon{},
+			Match_stats:   &rawModels.PlayerMatchStats{},
+			Position:      "0, 0, 0",
+			Forward:       "1, 0, 0",
+		}
+		p.order = append(p.order, steamID)
+	}
+	p.current = steamID
+	return p
+}
+
+// RemovePlayer removes a player, e.g. one who disconnected
+func (p *Payload) RemovePlayer(steamID string) *Payload {
+	delete(p.state.AllPlayers, steamID)
+	for i, id := range p.order {
+		if id == steamID {
+			p.order = append(p.order[:i:i], p.order[i+1:]...)
+			break
+		}
+	}
+	if p.current == steamID {
+		p.current = ""
+	}
+	if p.observed == steamID {
+		p.observed = ""
+	}
+	return p
+}
+
+// Players returns the steam ids of the players in the order they were added
+func (p *Payload) Players() []string {
+	return append([]string(nil), p.order...)
+}
+
+// player returns the selected player
+func (p *Payload) player(setter string) *rawModels.Player {
+	player, ok := p.state.AllPlayers[p.current]
+	if !ok {
+		panic("gsitest: " + setter + " called before Player")
+	}
+	return player
+}
+
+// Observe makes the selected player the one in the player block
+func (p *Payload) Observe() *Payload {
+	p.observed = p.player("Observe").Steamid
+	return p
+}
+
+// Name sets the selected player's name
+func (p *Payload) Name(name string) *Payload {
+	p.player("Name").Name = name
+	return p
+}
+
+// Team sets the selected player's side
+func (p *Payload) Team(side models.Side) *Payload {
+	p.player("Team").Team = rawModels.Side(side)
+	return p
+}
+
+// Slot sets the selected player's observer slot
+func (p *Payload) Slot(slot int) *Payload {
+	p.player("Slot").Observer_slot = slot
+	return p
+}
+
+// Position sets the selected player's position
+func (p *Payload) Position(position [3]float32) *Payload {
+	p.player("Position").Position = vector(position)
+	return p
+}
+
+// Health sets the selected player's health
+func (p *Payload) Health(health int) *Payload {
+	p.player("Health").State.Health = health
+	return p
+}
+
+// Armor sets the selected player's armor
+func (p *Payload) Armor(armor int) *Payload {
+	p.player("Armor").State.Armor = armor
+	return p
+}
+
+// Helmet sets whether the selected player has a helmet
+func (p *Payload) Helmet(helmet bool) *Payload {
+	p.player("Helmet").State.Helmet = helmet
+	return p
+}
+
+// DefuseKit sets whether the selected player has a defuse kit
+func (p *Payload) DefuseKit(kit bool) *Payload {
+	p.player("DefuseKit").State.DefuseKit = kit
+	return p
+}
+
+// Money sets the selected player's money
+func (p *Payload) Money(money int) *Payload {
+	p.player("Money").State.Money = money
+	return p
+}
+
+// EquipValue sets the selected player's equipment value
+func (p *Payload) EquipValue(value int) *Payload {
+	p.player("EquipValue").State.Equip_value = value
+	return p
+}
+
+// RoundKills sets the selected player's kills and headshot kills this round
+func (p *Payload) RoundKills(kills, headshots int) *Payload {
+	state := p.player("RoundKills").State
+	state.Round_kills = kills
+	state.Round_killhs = headshots
+	return p
+}
+
+// RoundDamage sets the selected player's damage dealt this round
+func (p *Payload) RoundDamage(damage int) *Payload {
+	p.player("RoundDamage").State.Round_totaldmg = damage
+	return p
+}
+
+// Stats sets the selected player's match kills, assists and deaths
+func (p *Payload) Stats(kills, assists, deaths int) *Payload {
+	stats := p.player("Stats").Match_stats
+	stats.Kills = kills
+	stats.Assists = assists
+	stats.Deaths = deaths
+	return p
+}
+
+// MVPs sets the selected player's MVP count
+func (p *Payload) MVPs(mvps int) *Payload {
+	p.player("MVPs").Match_stats.Mvps = mvps
+	return p
+}
+
+// Weapon adds a weapon to the selected player, or updates the one with the
+// same name. Weapons keep the weapon_N slots of the game payload.
+func (p *Payload) Weapon(name string, weaponType models.WeaponType, state models.WeaponState) *Payload {
+	player := p.player("Weapon")
+	for _, weapon := range player.Weapons {
+		if weapon.Name == name {
+			weapon.Type = rawModels.WeaponType(weaponType)
+			weapon.State = rawModels.WeaponState(state)
+			return p
+		}
+	}
+	player.Weapons[nextWeaponSlot(player.Weapons)] = &rawModels.Weapon{
+		Name:  name,
+		Type:  rawModels.WeaponType(weaponType),
+		State: rawModels.WeaponState(state),
+	}
+	return p
+}
+
+// AmmoReserve sets the reserve ammo of one of the selected player's
+// weapons; for grenades it is how many of that kind are carried
+func (p *Payload) AmmoReserve(name string, reserve int) *Payload {
+	for _, weapon := range p.player("AmmoReserve").Weapons {
+		if weapon.Name == name {
+			weapon.Ammo_reserve = reserve
+		}
+	}
+	return p
+}
+
+// DropWeapon removes a weapon from the selected player
+func (p *Payload) DropWeapon(name string) *Payload {
+	player := p.player("DropWeapon")
+	for slot, weapon := range player.Weapons {
+		if weapon.Name == name {
+			delete(player.Weapons, slot)
+		}
+	}
+	return p
+}
+
+// nextWeaponSlot returns the first free weapon_N key
+func nextWeaponSlot(weapons map[string]*rawModels.Weapon) string {
+	for i := 0; ; i++ {
+		slot := "weapon_" + strconv.Itoa(i)
+		if _, ok := weapons[slot]; !ok {
+			return slot
+		}
+	}
+}
+
+func vector(v [3]float32) string {
+	return fmt.Sprintf("%g, %g, %g", v[0], v[1], v[2])
+}
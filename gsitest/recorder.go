@@ -0,0 +1,100 @@
+package gsitest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+)
+
+// Recorder collects every event published to a source, for assertions
+type Recorder struct {
+	mu     sync.Mutex
+	events []cs2gsi.GameEvent
+}
+
+// NewRecorder records the events published to src until the test ends
+func NewRecorder(t testing.TB, src cs2gsi.EventSource) *Recorder {
+	r := &Recorder{}
+	sub := cs2gsi.SubscribeAllTo(src, func(event cs2gsi.GameEvent) {
+		r.mu.Lock()
+		r.events = append(r.events, event)
+		r.mu.Unlock()
+	})
+	t.Cleanup(sub.Unsubscribe)
+	return r
+}
+
+// Events returns the recorded events in publication order
+func (r *Recorder) Events() []cs2gsi.GameEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]cs2gsi.GameEvent(nil), r.events...)
+}
+
+// Names returns the names of the recorded events in publication order
+func (r *Recorder) Names() []string {
+	events := r.Events()
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event.EventName()
+	}
+	return names
+}
+
+// Reset forgets the recorded events
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.events = nil
+	r.mu.Unlock()
+}
+
+// EventsOf returns the recorded events published under name
+func EventsOf[T any](r *Recorder, name cs2gsi.EventName[T]) []cs2gsi.Event[T] {
+	var events []cs2gsi.Event[T]
+	for _, event := range r.Events() {
+		if typed, ok := event.(cs2gsi.Event[T]); ok && typed.Name == string(name) {
+			events = append(events, typed)
+		}
+	}
+	return events
+}
+
+// ExpectEvent fails the test unless exactly one event was published under
+// name, and returns it
+func ExpectEvent[T any](t testing.TB, r *Recorder, name cs2gsi.EventName[T]) cs2gsi.Event[T] {
+	t.Helper()
+	events := EventsOf(r, name)
+	if len(events) != 1 {
+		t.Fatalf("expected one %s event, got %d (recorded: %s)", name, len(events), strings.Join(r.Names(), ", "))
+	}
+	return events[0]
+}
+
+// ExpectNoEvent fails the test if an event was published under name
+func ExpectNoEvent[T any](t testing.TB, r *Recorder, name cs2gsi.EventName[T]) {
+	t.Helper()
+	if events := EventsOf(r, name); len(events) > 0 {
+		t.Fatalf("expected no %s event, got %d", name, len(events))
+	}
+}
+
+// ExpectNames fails the test unless the recorded event names, filtered to
+// the ones in names, are exactly names in order
+func ExpectNames(t testing.TB, r *Recorder, names ...string) {
+	t.Helper()
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var got []string
+	for _, name := range r.Names() {
+		if wanted[name] {
+			got = append(got, name)
+		}
+	}
+	if strings.Join(got, ",") != strings.Join(names, ",") {
+		t.Fatalf("expected events [%s], got [%s]", strings.Join(names, ", "), strings.Join(got, ", "))
+	}
+}
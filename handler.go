@@ -1,24 +1,131 @@
 package cs2gsi
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 )
 
-// Listen starts the HTTP server and handles incoming game state requests
+var ErrServerRunning = errors.New("server is already running")
+
+// Listen starts the HTTP server and handles incoming game state requests.
+// It blocks until the server fails or Shutdown is called.
 func (gsi *CS2GSI) Listen() error {
+	return gsi.ListenContext(context.Background())
+}
+
+// ListenContext starts the HTTP server and blocks until ctx is cancelled,
+// Shutdown is called or the server fails. In-flight requests are drained
+// (bounded by Config.ShutdownTimeout) before it returns. It can be called
+// again once it has returned.
+func (gsi *CS2GSI) ListenContext(ctx context.Context) error {
+	srv := gsi.newServer()
+
+	gsi.serverMu.Lock()
+	if gsi.server != nil {
+		gsi.serverMu.Unlock()
+		return ErrServerRunning
+	}
+	gsi.server = srv
+	gsi.serverMu.Unlock()
+
+	defer func() {
+		gsi.serverMu.Lock()
+		if gsi.server == srv {
+			gsi.server = nil
+		}
+		gsi.serverMu.Unlock()
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		gsi.logger.Info("starting CS2 GSI server", "address", srv.Addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), gsi.config.ShutdownTimeout)
+		defer cancel()
+
+		gsi.logger.Info("stopping CS2 GSI server", "address", srv.Addr)
+		err := srv.Shutdown(shutdownCtx)
+		<-errCh
+		return err
+	}
+}
+
+// Shutdown gracefully stops a server started with Listen or ListenContext,
+// waiting for in-flight requests until ctx expires. It is a no-op when no
+// server is running.
+func (gsi *CS2GSI) Shutdown(ctx context.Context) error {
+	gsi.serverMu.Lock()
+	srv := gsi.server
+	gsi.serverMu.Unlock()
+
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+// newServer builds the HTTP server with the configured address and timeouts
+func (gsi *CS2GSI) newServer() *http.Server {
 	mux := http.NewServeMux()
-	mux.HandleFunc("POST /", func(w http.ResponseWriter, r *http.Request) {
+	mux.Handle("POST /", gsi.Handler())
+	mux.Handle("POST /mirv", gsi.MIRVHandler())
+	mux.Handle("GET /ws", gsi.WebSocketHandler())
+	mux.Handle("GET /events", gsi.EventStreamHandler())
+
+	state := gsi.StateHandler()
+	mux.Handle("GET /state", state)
+	mux.Handle("GET /state/", state)
+
+	srv := &http.Server{
+		Addr:              gsi.config.ServerAddr,
+		Handler:           mux,
+		ReadTimeout:       gsi.config.ReadTimeout,
+		ReadHeaderTimeout: gsi.config.ReadHeaderTimeout,
+		WriteTimeout:      gsi.config.WriteTimeout,
+		IdleTimeout:       gsi.config.IdleTimeout,
+	}
+	// Streaming clients never finish on their own; disconnect them so that
+	// Shutdown does not wait for its full timeout
+	srv.RegisterOnShutdown(gsi.hub.closeAll)
+	return srv
+}
+
+// Handler returns the GSI ingest endpoint as an http.Handler so it can be
+// mounted on an existing mux, e.g. mux.Handle("POST /gsi", gsi.Handler()).
+func (gsi *CS2GSI) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if err := gsi.handleGameStateRequest(w, r); err != nil {
 			gsi.logger.Error("failed to handle game state request", "error", err)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
 		}
 	})
+}
 
-	gsi.logger.Info("starting CS2 GSI server", "address", gsi.config.ServerAddr)
-	return http.ListenAndServe(gsi.config.ServerAddr, mux)
+// MIRVHandler returns the HLAE/MIRV game event endpoint as an http.Handler.
+// The event type is read from the "event" query parameter, falling back to
+// the "name" field of the payload. When Config.ExpectedToken is set, the
+// token must be sent in the X-Auth-Token header or the "token" query
+// parameter.
+func (gsi *CS2GSI) MIRVHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := gsi.handleMIRVRequest(w, r); err != nil {
+			gsi.logger.Error("failed to handle MIRV request", "error", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+	})
 }
 
 // handleGameStateRequest processes incoming game state requests from CS2
@@ -60,3 +167,49 @@ func (gsi *CS2GSI) handleGameStateRequest(w http.ResponseWriter, r *http.Request
 	w.Write([]byte("OK"))
 	return nil
 }
+
+// handleMIRVRequest processes incoming HLAE/MIRV game events
+func (gsi *CS2GSI) handleMIRVRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		gsi.logger.Warn("invalid HTTP method", "method", r.Method, "remote_addr", r.RemoteAddr)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil
+	}
+
+	if err := gsi.validateMIRVToken(r); err != nil {
+		gsi.logger.Warn("invalid auth token", "remote_addr", r.RemoteAddr)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1024*1024)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		gsi.logger.Error("failed to read request body", "error", err, "remote_addr", r.RemoteAddr)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	eventType := r.URL.Query().Get("event")
+	if eventType == "" {
+		eventType = mirvEventName(body)
+	}
+
+	if _, err := gsi.DigestMIRV(body, eventType); err != nil {
+		switch {
+		case errors.Is(err, ErrMIRVNoPriorState):
+			http.Error(w, "No game state received yet", http.StatusConflict)
+		case errors.Is(err, ErrMIRVUnknownEvent):
+			http.Error(w, "Unknown MIRV event type", http.StatusBadRequest)
+		default:
+			gsi.logger.Error("failed to process MIRV event", "error", err, "remote_addr", r.RemoteAddr)
+			http.Error(w, "Invalid MIRV event data", http.StatusBadRequest)
+		}
+		return nil
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+	return nil
+}
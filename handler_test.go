@@ -0,0 +1,199 @@
+package cs2gsi
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+func waitForServer(t *testing.T, addr string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server at %s did not start", addr)
+}
+
+func TestListenContextRestart(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	addr := freeAddr(t)
+	gsi := New(Config{ServerAddr: addr})
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- gsi.ListenContext(ctx) }()
+
+		waitForServer(t, addr)
+		resp, err := http.Post("http://"+addr+"/", "application/json", bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("run %d: post: %v", i, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("run %d: status = %d, want 200", i, resp.StatusCode)
+		}
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("run %d: ListenContext: %v", i, err)
+		}
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	addr := freeAddr(t)
+	gsi := New(Config{ServerAddr: addr})
+
+	done := make(chan error, 1)
+	go func() { done <- gsi.Listen() }()
+	waitForServer(t, addr)
+
+	if err := gsi.ListenContext(context.Background()); err != ErrServerRunning {
+		t.Fatalf("second ListenContext err = %v, want ErrServerRunning", err)
+	}
+
+	if err := gsi.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	if err := gsi.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown without server: %v", err)
+	}
+}
+
+func TestHandlerMountedOnCustomMux(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	kill, err := os.ReadFile("testdata/mirv/player_death.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(NewConfig())
+	mux := http.NewServeMux()
+	mux.Handle("POST /gsi", gsi.Handler())
+	mux.Handle("POST /gsi/mirv", gsi.MIRVHandler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/gsi/mirv", "application/json", bytes.NewReader(kill))
+	if err != nil {
+		t.Fatalf("post mirv: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("mirv before gsi status = %d, want 409", resp.StatusCode)
+	}
+
+	resp, err = http.Post(srv.URL+"/gsi", "application/json", bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("post gsi: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("gsi status = %d, want 200", resp.StatusCode)
+	}
+	if gsi.Snapshot() == nil {
+		t.Fatal("expected snapshot after POST")
+	}
+
+	resp, err = http.Post(srv.URL+"/gsi/mirv", "application/json", bytes.NewReader(kill))
+	if err != nil {
+		t.Fatalf("post mirv: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("mirv status = %d, want 200", resp.StatusCode)
+	}
+
+	resp, err = http.Post(srv.URL+"/gsi/mirv?event=unknown", "application/json", bytes.NewReader(kill))
+	if err != nil {
+		t.Fatalf("post mirv: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unknown mirv status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestMIRVHandlerToken(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	kill, err := os.ReadFile("testdata/mirv/player_death.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{Bus: NewBus(), ExpectedToken: "secret"})
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	srv := httptest.NewServer(gsi.MIRVHandler())
+	defer srv.Close()
+
+	post := func(url, token string) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(kill))
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("X-Auth-Token", token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("post mirv: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for _, tc := range []struct {
+		name, url, token string
+		want             int
+	}{
+		{"no token", srv.URL, "", http.StatusUnauthorized},
+		{"wrong token", srv.URL, "wrong", http.StatusUnauthorized},
+		{"header", srv.URL, "secret", http.StatusOK},
+		{"query", srv.URL + "?token=secret", "", http.StatusOK},
+	} {
+		if got := post(tc.url, tc.token); got != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package cs2gsi
+
+import (
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// killCredit is how much a player's kill and damage counters moved since
+// the last state
+type killCredit struct {
+	player    *models.Player
+	kills     int
+	headshots int
+	damage    int
+}
+
+// detectDeathEvents publishes Death for every player whose health dropped
+// to zero, followed by an InferredKill attributing it to an enemy whose
+// kill counters moved. Once MIRV has delivered a Kill the MIRV kill feed is
+// authoritative and only Death is published.
+func (gsi *CS2GSI) detectDeathEvents(state *models.State) error {
+	last := gsi.last
+	if last == nil || last.Map == nil || state.Map == nil || last.Map.Name != state.Map.Name {
+		return nil
+	}
+
+	var victims []*models.Player
+	for _, player := range sortedPlayers(state.AllPlayers) {
+		previous, ok := last.AllPlayers[player.SteamId]
+		if !ok || player.State == nil || previous.State == nil {
+			continue
+		}
+		if player.State.Health == 0 && previous.State.Health > 0 {
+			victims = append(victims, player)
+		}
+	}
+	if len(victims) == 0 {
+		return nil
+	}
+
+	credits := killCredits(last, state)
+	for i, victim := range victims {
+		gsi.logger.Info("Death detected", "player", victim.Name)
+		gsi.publishDeath(victim)
+		if gsi.mirvKills {
+			continue
+		}
+
+		// Deaths of the victim's side still to attribute, this one included
+		pending := 0
+		for _, other := range victims[i:] {
+			if sameSide(other, victim) {
+				pending++
+			}
+		}
+		health := last.AllPlayers[victim.SteamId].State.Health
+		kill := attributeKill(victim, health, credits, pending)
+		if kill.Attacker != nil {
+			kill.Weapon = activeWeapon(kill.Attacker)
+			if kill.Weapon == nil {
+				kill.Weapon = activeWeapon(last.AllPlayers[kill.Attacker.SteamId])
+			}
+		}
+		gsi.publishInferredKill(kill)
+	}
+
+	return nil
+}
+
+// detectHurtEvents publishes InferredHurt for every living player whose
+// health or armor went down, with the enemy whose damage counter moved by
+// the same amount as a best guess for the attacker
+func (gsi *CS2GSI) detectHurtEvents(state *models.State) error {
+	last := gsi.last
+	if last == nil || last.Map == nil || state.Map == nil || last.Map.Name != state.Map.Name {
+		return nil
+	}
+
+	credits := damageCredits(last, state)
+	newRound := last.Map.Round != state.Map.Round
+	var hurts []*models.InferredHurtEvent
+	for _, player := range sortedPlayers(state.AllPlayers) {
+		previous, ok := last.AllPlayers[player.SteamId]
+		if !ok || player.State == nil || previous.State == nil || previous.State.Health == 0 {
+			continue
+		}
+		// Armor is reset when teams swap sides
+		if player.Team != nil && previous.Team != nil && player.Team.Side != previous.Team.Side {
+			continue
+		}
+		dmgHealth := max(previous.State.Health-player.State.Health, 0)
+		dmgArmor := max(previous.State.Armor-player.State.Armor, 0)
+		if dmgHealth == 0 && dmgArmor == 0 {
+			continue
+		}
+		// Armor lost between rounds without anyone dealing damage was not a hit
+		if dmgHealth == 0 && newRound && len(credits) == 0 {
+			continue
+		}
+		hurts = append(hurts, &models.InferredHurtEvent{
+			Victim:    player,
+			Health:    player.State.Health,
+			Armor:     player.State.Armor,
+			DmgHealth: dmgHealth,
+			DmgArmor:  dmgArmor,
+		})
+	}
+	if len(hurts) == 0 {
+		return nil
+	}
+
+	for _, hurt := range hurts {
+		attributeHurt(hurt, credits)
+		if hurt.Attacker != nil {
+			hurt.Weapon = activeWeapon(hurt.Attacker)
+			if hurt.Weapon == nil {
+				hurt.Weapon = activeWeapon(last.AllPlayers[hurt.Attacker.SteamId])
+			}
+		}
+		gsi.publishInferredHurt(hurt)
+	}
+
+	return nil
+}
+
+// damageCredits returns the players whose round damage went up, by
+// observer slot
+func damageCredits(last, state *models.State) []*killCredit {
+	var credits []*killCredit
+	for _, player := range sortedPlayers(state.AllPlayers) {
+		previous, ok := last.AllPlayers[player.SteamId]
+		if !ok || player.State == nil || previous.State == nil {
+			continue
+		}
+		if damage := player.State.Round_totaldmg - previous.State.Round_totaldmg; damage > 0 {
+			credits = append(credits, &killCredit{player: player, damage: damage})
+		}
+	}
+	return credits
+}
+
+// attributeHurt picks the attacker of hurt among the enemies with damage
+// left to attribute and consumes the damage. Round damage only counts
+// health damage, so that is what is matched.
+func attributeHurt(hurt *models.InferredHurtEvent, credits []*killCredit) {
+	var candidates []*killCredit
+	for _, credit := range credits {
+		if credit.damage > 0 && !sameSide(credit.player, hurt.Victim) {
+			candidates = append(candidates, credit)
+		}
+	}
+	if len(candidates) == 0 || hurt.DmgHealth == 0 {
+		return
+	}
+
+	best := candidates[0]
+	matches := 0
+	for _, candidate := range candidates {
+		if candidate.damage == hurt.DmgHealth {
+			matches++
+		}
+		if damageMiss(candidate.damage, hurt.DmgHealth) < damageMiss(best.damage, hurt.DmgHealth) {
+			best = candidate
+		}
+	}
+
+	switch {
+	case len(candidates) == 1:
+		hurt.Confidence = min(float64(best.damage)/float64(hurt.DmgHealth), 1)
+	case matches == 1 && best.damage == hurt.DmgHealth:
+		hurt.Confidence = 0.75
+	default:
+		hurt.Confidence = 1 / float64(len(candidates))
+	}
+
+	hurt.Attacker = best.player
+	best.damage = max(best.damage-hurt.DmgHealth, 0)
+}
+
+// killCredits returns the players whose kill count went up, by observer slot
+func killCredits(last, state *models.State) []*killCredit {
+	var credits []*killCredit
+	for _, player := range sortedPlayers(state.AllPlayers) {
+		previous, ok := last.AllPlayers[player.SteamId]
+		if !ok || player.State == nil || previous.State == nil {
+			continue
+		}
+
+		// Round counters reset with the round, match counters do not
+		kills := player.State.Round_kills - previous.State.Round_kills
+		if player.Match_stats != nil && previous.Match_stats != nil {
+			kills = max(kills, player.Match_stats.Kills-previous.Match_stats.Kills)
+		}
+		if kills <= 0 {
+			continue
+		}
+		credits = append(credits, &killCredit{
+			player:    player,
+			kills:     kills,
+			headshots: max(player.State.Round_killhs-previous.State.Round_killhs, 0),
+			damage:    max(player.State.Round_totaldmg-previous.State.Round_totaldmg, 0),
+		})
+	}
+	return credits
+}
+
+// attributeKill picks the killer of victim among the enemies with kills
+// left to attribute and consumes the kill. With several candidates the one
+// whose damage increase best matches the victim's remaining health wins.
+func attributeKill(victim *models.Player, health int, credits []*killCredit, pending int) *models.InferredKillEvent {
+	kill := &models.InferredKillEvent{Victim: victim}
+
+	var candidates []*killCredit
+	total := 0
+	for _, credit := range credits {
+		if credit.kills > 0 && !sameSide(credit.player, victim) {
+			candidates = append(candidates, credit)
+			total += credit.kills
+		}
+	}
+	if len(candidates) == 0 {
+		return kill
+	}
+
+	best := candidates[0]
+	matches := 0
+	for _, candidate := range candidates {
+		if candidate.damage >= health {
+			matches++
+		}
+		if damageMiss(candidate.damage, health) < damageMiss(best.damage, health) {
+			best = candidate
+		}
+	}
+
+	switch {
+	case len(candidates) == 1:
+		kill.Confidence = min(float64(total)/float64(pending), 1)
+	case matches == 1 && best.damage >= health:
+		kill.Confidence = 0.75
+	default:
+		kill.Confidence = 1 / float64(len(candidates))
+	}
+
+	kill.Attacker = best.player
+	best.kills--
+	if best.headshots > 0 {
+		kill.Headshot = true
+		best.headshots--
+	}
+	best.damage = max(best.damage-health, 0)
+	return kill
+}
+
+// damageMiss is how far a damage increase is from the health it should
+// have taken; falling short counts more than overshooting
+func damageMiss(damage, health int) int {
+	if damage < health {
+		return 2 * (health - damage)
+	}
+	return damage - health
+}
+
+func sameSide(a, b *models.Player) bool {
+	return a.Team != nil && b.Team != nil && a.Team.Side == b.Team.Side
+}
@@ -0,0 +1,204 @@
+package cs2gsi_test
+
+import (
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	"github.com/nescabir/go-cs2-gsi/gsitest"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+const (
+	ct1 = "76561198000000001"
+	ct2 = "76561198000000002"
+	t1  = "76561198000000006"
+	t2  = "76561198000000007"
+)
+
+// newRecorded returns an instance on a private bus and a recorder of its
+// events
+func newRecorded(t *testing.T) (*cs2gsi.CS2GSI, *gsitest.Recorder) {
+	t.Helper()
+	gsi := cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus()})
+	return gsi, gsitest.NewRecorder(t, gsi)
+}
+
+// duel is a live round with ct1 against t1, both holding their rifle
+func duel() *gsitest.Payload {
+	return gsitest.NewPayload().
+		Player(ct1).Team(models.CTSide).Weapon("weapon_m4a1", models.WeaponTypeRifle, models.WeaponStateActive).
+		Player(t1).Team(models.TSide).Weapon("weapon_ak47", models.WeaponTypeRifle, models.WeaponStateActive)
+}
+
+func TestInferKillSingleAttacker(t *testing.T) {
+	gsi, rec := newRecorded(t)
+
+	before := duel()
+	before.Digest(t, gsi)
+	before.Clone().
+		Player(ct1).Health(0).
+		Player(t1).RoundKills(1, 1).RoundDamage(100).Stats(1, 0, 0).
+		Digest(t, gsi)
+
+	death := gsitest.ExpectEvent(t, rec, cs2gsi.Death)
+	if death.Data.SteamId != ct1 {
+		t.Fatalf("death = %s, want %s", death.Data.SteamId, ct1)
+	}
+	kill := gsitest.ExpectEvent(t, rec, cs2gsi.InferredKill).Data
+	if kill.Attacker == nil || kill.Attacker.SteamId != t1 {
+		t.Fatalf("attacker = %v, want %s", kill.Attacker, t1)
+	}
+	if !kill.Headshot || kill.Confidence != 1 {
+		t.Fatalf("headshot/confidence = %t/%v, want true/1", kill.Headshot, kill.Confidence)
+	}
+	if kill.Weapon == nil || kill.Weapon.Name != "weapon_ak47" {
+		t.Fatalf("weapon = %v, want the active ak47", kill.Weapon)
+	}
+	gsitest.ExpectNames(t, rec, string(models.Death), string(models.InferredKill))
+}
+
+func TestInferKillByDamage(t *testing.T) {
+	gsi, rec := newRecorded(t)
+
+	before := duel().Player(ct1).Health(40).Player(t2).Team(models.TSide)
+	before.Digest(t, gsi)
+	before.Clone().
+		Player(ct1).Health(0).
+		Player(t1).RoundKills(1, 0).RoundDamage(12).
+		Player(t2).RoundKills(1, 0).RoundDamage(40).
+		Digest(t, gsi)
+
+	kill := gsitest.ExpectEvent(t, rec, cs2gsi.InferredKill).Data
+	if kill.Attacker == nil || kill.Attacker.SteamId != t2 {
+		t.Fatalf("attacker = %v, want %s whose damage matches", kill.Attacker, t2)
+	}
+	if kill.Confidence != 0.75 {
+		t.Fatalf("confidence = %v, want 0.75", kill.Confidence)
+	}
+}
+
+func TestInferKillWithoutAttacker(t *testing.T) {
+	gsi, rec := newRecorded(t)
+
+	// A teammate's kill count does not explain the death
+	before := gsitest.NewPayload().Player(ct1).Player(ct2)
+	before.Digest(t, gsi)
+	before.Clone().
+		Player(ct1).Health(0).
+		Player(ct2).RoundKills(1, 0).RoundDamage(100).
+		Digest(t, gsi)
+
+	gsitest.ExpectEvent(t, rec, cs2gsi.Death)
+	kill := gsitest.ExpectEvent(t, rec, cs2gsi.InferredKill).Data
+	if kill.Attacker != nil || kill.Confidence != 0 {
+		t.Fatalf("kill = %+v, want no attacker", kill)
+	}
+}
+
+func TestInferKillMapChange(t *testing.T) {
+	gsi, rec := newRecorded(t)
+
+	before := duel()
+	before.Digest(t, gsi)
+	before.Clone().Map("de_mirage", 0).Player(ct1).Health(0).Digest(t, gsi)
+
+	gsitest.ExpectNoEvent(t, rec, cs2gsi.Death)
+	gsitest.ExpectNoEvent(t, rec, cs2gsi.InferredKill)
+}
+
+func TestInferHurtMatchesDamage(t *testing.T) {
+	gsi, rec := newRecorded(t)
+
+	before := duel().
+		Player(ct1).Armor(100).
+		Player(ct2).
+		Player(t2).Team(models.TSide)
+	before.Digest(t, gsi)
+	before.Clone().
+		Player(ct1).Health(73).Armor(0).
+		Player(ct2).Health(60).
+		Player(t1).RoundDamage(40).
+		Player(t2).RoundDamage(27).
+		Digest(t, gsi)
+
+	hurts := gsitest.EventsOf(rec, cs2gsi.InferredHurt)
+	if len(hurts) != 2 {
+		t.Fatalf("hurts = %d, want 2", len(hurts))
+	}
+	want := []struct {
+		victim, attacker    string
+		dmgHealth, dmgArmor int
+	}{
+		{ct1, t2, 27, 100},
+		{ct2, t1, 40, 0},
+	}
+	for i, w := range want {
+		hurt := hurts[i].Data
+		if hurt.Victim.SteamId != w.victim || hurt.Attacker == nil || hurt.Attacker.SteamId != w.attacker {
+			t.Fatalf("hurt %d = %s by %v, want %s by %s", i, hurt.Victim.SteamId, hurt.Attacker, w.victim, w.attacker)
+		}
+		if hurt.DmgHealth != w.dmgHealth || hurt.DmgArmor != w.dmgArmor {
+			t.Fatalf("hurt %d damage = %d/%d, want %d/%d", i, hurt.DmgHealth, hurt.DmgArmor, w.dmgHealth, w.dmgArmor)
+		}
+	}
+	if hurts[0].Data.Confidence != 0.75 {
+		t.Fatalf("confidence = %v, want 0.75 for a unique match", hurts[0].Data.Confidence)
+	}
+}
+
+func TestInferHurtWithoutAttacker(t *testing.T) {
+	gsi, rec := newRecorded(t)
+
+	// Fall damage, and a player already dead last tick respawning
+	before := gsitest.NewPayload().Player(ct1).Player(ct2).Health(0)
+	before.Digest(t, gsi)
+	before.Clone().
+		Player(ct1).Health(88).
+		Player(ct2).Health(100).
+		Digest(t, gsi)
+
+	hurt := gsitest.ExpectEvent(t, rec, cs2gsi.InferredHurt).Data
+	if hurt.Victim.SteamId != ct1 {
+		t.Fatalf("victim = %s, want %s", hurt.Victim.SteamId, ct1)
+	}
+	if hurt.Attacker != nil || hurt.Confidence != 0 || hurt.DmgHealth != 12 {
+		t.Fatalf("hurt = %+v, want 12 damage without an attacker", hurt)
+	}
+}
+
+func TestInferHurtSkipsRoundReset(t *testing.T) {
+	gsi, rec := newRecorded(t)
+
+	// Sides swap at halftime, and ct2 loses the armor bought last round
+	before := duel().Map("de_dust2", 11).
+		Player(ct1).Armor(100).
+		Player(t1).Armor(100).
+		Player(ct2).Team(models.CTSide).Armor(100)
+	before.Digest(t, gsi)
+	before.Clone().Map("de_dust2", 12).
+		Player(ct1).Team(models.TSide).Armor(0).
+		Player(t1).Team(models.CTSide).Armor(0).
+		Player(ct2).Armor(0).
+		Digest(t, gsi)
+
+	gsitest.ExpectNoEvent(t, rec, cs2gsi.InferredHurt)
+}
+
+func TestInferKillStopsWithMIRV(t *testing.T) {
+	gsi, rec := newRecorded(t)
+
+	before := duel()
+	before.Digest(t, gsi)
+	mirv := []byte(`{"name":"player_death","keys":{"userid":{"xuid":"` + t1 + `"},"attacker":{"xuid":"` + ct1 + `"},"assister":{"xuid":"0"},"weapon":"m4a1","headshot":false}}`)
+	if _, err := gsi.DigestMIRV(mirv, cs2gsi.MIRVEventPlayerDeath); err != nil {
+		t.Fatalf("DigestMIRV: %v", err)
+	}
+	before.Clone().
+		Player(ct1).RoundKills(1, 0).RoundDamage(100).
+		Player(t1).Health(0).
+		Digest(t, gsi)
+
+	gsitest.ExpectEvent(t, rec, cs2gsi.Kill)
+	gsitest.ExpectEvent(t, rec, cs2gsi.Death)
+	gsitest.ExpectNoEvent(t, rec, cs2gsi.InferredKill)
+}
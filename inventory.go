@@ -0,0 +1,119 @@
+package cs2gsi
+
+import (
+	"sort"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// detectWeaponEvents diffs the inventory of every player alive in both
+// states, matching weapons by name since the game's weapon_N slots shift
+// when a weapon is dropped. A weapon that appears is a purchase when the
+// player's money went down while buying is possible and a pickup
+// otherwise; a grenade that disappears outside freezetime was thrown.
+func (gsi *CS2GSI) detectWeaponEvents(state *models.State) error {
+	last := gsi.last
+	if last == nil || last.Map == nil || state.Map == nil || last.Map.Name != state.Map.Name {
+		return nil
+	}
+
+	freezetime := state.Round != nil && state.Round.Phase == models.RoundPhaseFreezeTime
+	for _, player := range sortedPlayers(state.AllPlayers) {
+		previous, ok := last.AllPlayers[player.SteamId]
+		if !ok || player.State == nil || previous.State == nil {
+			continue
+		}
+		if player.State.Health == 0 || previous.State.Health == 0 {
+			continue
+		}
+		// Inventories are reset when teams swap sides
+		if player.Team != nil && previous.Team != nil && player.Team.Side != previous.Team.Side {
+			continue
+		}
+		purchased := player.State.Money < previous.State.Money && canBuy(state)
+		had, has := weaponsByName(previous.Weapons), weaponsByName(player.Weapons)
+
+		for _, name := range weaponNames(had) {
+			before := had[name]
+			after, kept := has[name]
+			if kept {
+				// Grenades of the same kind share an entry counted by its reserve
+				if before.Type == models.WeaponTypeGrenade && after.Ammo_reserve < before.Ammo_reserve && !freezetime {
+					gsi.publishGrenadeThrow(&models.WeaponEvent{Player: player, Weapon: after})
+				}
+				continue
+			}
+			switch {
+			case before.Type == models.WeaponTypeGrenade && !freezetime:
+				gsi.publishGrenadeThrow(&models.WeaponEvent{Player: player, Weapon: before})
+			case before.Type == models.WeaponTypeC4 && (state.Bomb == nil || state.Bomb.State != models.BombStateDropped):
+				// Planted, reported by the bomb events
+			default:
+				gsi.publishWeaponDrop(&models.WeaponEvent{Player: player, Weapon: before})
+			}
+		}
+
+		for _, name := range weaponNames(has) {
+			after := has[name]
+			before, kept := had[name]
+			if kept && (after.Type != models.WeaponTypeGrenade || after.Ammo_reserve <= before.Ammo_reserve) {
+				continue
+			}
+			if purchased {
+				gsi.publishWeaponPurchase(&models.WeaponEvent{Player: player, Weapon: after})
+			} else {
+				gsi.publishWeaponPickup(&models.WeaponEvent{Player: player, Weapon: after})
+			}
+		}
+
+		held, wasHeld := activeWeapon(player), activeWeapon(previous)
+		if held != nil && wasHeld != nil && held.Name != wasHeld.Name {
+			gsi.publishWeaponSwitch(&models.WeaponEvent{Player: player, Weapon: held, Previous: wasHeld})
+		}
+	}
+
+	return nil
+}
+
+// activeWeapon returns the weapon a player holds, or nil
+func activeWeapon(player *models.Player) *models.Weapon {
+	if player == nil {
+		return nil
+	}
+	for _, weapon := range player.Weapons {
+		if weapon.State == models.WeaponStateActive || weapon.State == models.WeaponStateReloading {
+			return weapon
+		}
+	}
+	return nil
+}
+
+// canBuy reports whether players may be buying in state. GSI does not
+// expose the buy time, so the whole live phase counts.
+func canBuy(state *models.State) bool {
+	if state.Map.Phase == models.MapPhaseWarmup {
+		return true
+	}
+	return state.Round != nil && (state.Round.Phase == models.RoundPhaseFreezeTime || state.Round.Phase == models.RoundPhaseLive)
+}
+
+// weaponsByName keys weapons by weapon name instead of inventory slot
+func weaponsByName(weapons map[string]*models.Weapon) map[string]*models.Weapon {
+	byName := make(map[string]*models.Weapon, len(weapons))
+	for _, weapon := range weapons {
+		if weapon != nil && weapon.Name != "" {
+			byName[weapon.Name] = weapon
+		}
+	}
+	return byName
+}
+
+// weaponNames returns the names of weapons in a stable order
+func weaponNames(weapons map[string]*models.Weapon) []string {
+	names := make([]string, 0, len(weapons))
+	for name := range weapons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,134 @@
+package cs2gsi_test
+
+import (
+	"reflect"
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	"github.com/nescabir/go-cs2-gsi/gsitest"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// armed is a round in phase with ct1 holding a knife and the given weapons,
+// the knife active
+func armed(phase models.RoundPhase, money int, weapons ...string) *gsitest.Payload {
+	p := gsitest.NewPayload().RoundPhase(phase).
+		Player(ct1).Money(money).Weapon("weapon_knife", models.WeaponTypeKnife, models.WeaponStateActive)
+	for _, name := range weapons {
+		p.Weapon(name, weaponTypes[name], models.WeaponStateHolstered)
+	}
+	return p
+}
+
+var weaponTypes = map[string]models.WeaponType{
+	"weapon_glock":        models.WeaponTypePistol,
+	"weapon_usp_silencer": models.WeaponTypePistol,
+	"weapon_m4a1":         models.WeaponTypeRifle,
+	"weapon_mp9":          models.WeaponTypeSubmachineGun,
+	"weapon_smokegrenade": models.WeaponTypeGrenade,
+	"weapon_flashbang":    models.WeaponTypeGrenade,
+}
+
+// weaponEvents digests before then after and returns the published weapon
+// events as "name weapon" strings
+func weaponEvents(t *testing.T, before, after *gsitest.Payload) []string {
+	t.Helper()
+	gsi, rec := newRecorded(t)
+	before.Digest(t, gsi)
+	after.Digest(t, gsi)
+
+	var got []string
+	for _, event := range rec.Events() {
+		if e, ok := event.(cs2gsi.Event[*models.WeaponEvent]); ok {
+			got = append(got, e.Name+" "+e.Data.Weapon.Name)
+		}
+	}
+	return got
+}
+
+func TestWeaponEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		before *gsitest.Payload
+		after  func(p *gsitest.Payload) *gsitest.Payload
+		want   []string
+	}{
+		{
+			name:   "purchase in freezetime",
+			before: armed(models.RoundPhaseFreezeTime, 4000, "weapon_usp_silencer"),
+			after: func(p *gsitest.Payload) *gsitest.Payload {
+				return p.Money(900).Weapon("weapon_m4a1", models.WeaponTypeRifle, models.WeaponStateHolstered)
+			},
+			want: []string{"weaponPurchase weapon_m4a1"},
+		},
+		{
+			name:   "pickup without spending",
+			before: armed(models.RoundPhaseLive, 900, "weapon_usp_silencer"),
+			after: func(p *gsitest.Payload) *gsitest.Payload {
+				return p.Weapon("weapon_m4a1", models.WeaponTypeRifle, models.WeaponStateHolstered)
+			},
+			want: []string{"weaponPickup weapon_m4a1"},
+		},
+		{
+			name:   "drop and switch",
+			before: armed(models.RoundPhaseLive, 900, "weapon_m4a1", "weapon_usp_silencer"),
+			after: func(p *gsitest.Payload) *gsitest.Payload {
+				return p.DropWeapon("weapon_m4a1").
+					Weapon("weapon_knife", models.WeaponTypeKnife, models.WeaponStateHolstered).
+					Weapon("weapon_usp_silencer", models.WeaponTypePistol, models.WeaponStateActive)
+			},
+			want: []string{"weaponDrop weapon_m4a1", "weaponSwitch weapon_usp_silencer"},
+		},
+		{
+			name:   "last grenade of a kind thrown",
+			before: armed(models.RoundPhaseLive, 900, "weapon_usp_silencer", "weapon_smokegrenade").AmmoReserve("weapon_smokegrenade", 1),
+			after: func(p *gsitest.Payload) *gsitest.Payload {
+				return p.DropWeapon("weapon_smokegrenade")
+			},
+			want: []string{"grenadeThrow weapon_smokegrenade"},
+		},
+		{
+			name:   "one of two flashbangs thrown",
+			before: armed(models.RoundPhaseLive, 900, "weapon_flashbang").AmmoReserve("weapon_flashbang", 2),
+			after: func(p *gsitest.Payload) *gsitest.Payload {
+				return p.AmmoReserve("weapon_flashbang", 1)
+			},
+			want: []string{"grenadeThrow weapon_flashbang"},
+		},
+		{
+			name:   "grenade dropped in freezetime",
+			before: armed(models.RoundPhaseFreezeTime, 900, "weapon_usp_silencer", "weapon_smokegrenade"),
+			after: func(p *gsitest.Payload) *gsitest.Payload {
+				return p.DropWeapon("weapon_smokegrenade")
+			},
+			want: []string{"weaponDrop weapon_smokegrenade"},
+		},
+		{
+			// The rifle takes the slot the pistol left, weapon_1 in both payloads
+			name:   "weapon taking a freed slot",
+			before: armed(models.RoundPhaseLive, 900, "weapon_glock", "weapon_smokegrenade"),
+			after: func(p *gsitest.Payload) *gsitest.Payload {
+				return p.DropWeapon("weapon_glock").Weapon("weapon_m4a1", models.WeaponTypeRifle, models.WeaponStateHolstered)
+			},
+			want: []string{"weaponDrop weapon_glock", "weaponPickup weapon_m4a1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := tt.after(tt.before.Clone().Player(ct1))
+			if got := weaponEvents(t, tt.before, after); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeaponEventsSkipDeadPlayers(t *testing.T) {
+	before := armed(models.RoundPhaseLive, 900, "weapon_m4a1", "weapon_usp_silencer")
+	after := before.Clone().Player(ct1).Health(0).DropWeapon("weapon_m4a1").DropWeapon("weapon_usp_silencer")
+
+	if got := weaponEvents(t, before, after); len(got) != 0 {
+		t.Fatalf("events for a dying player = %q, want none", got)
+	}
+}
@@ -2,7 +2,11 @@ package cs2gsi
 
 import (
 	"log/slog"
+	"net/http"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Marlliton/slogpretty"
 	models "github.com/nescabir/go-cs2-gsi/models"
@@ -26,6 +30,17 @@ type CS2GSI struct {
 	payloadAcc          *payloadAcc
 	current             *models.State
 	last                *models.State
+	digestMu            sync.Mutex
+	stateMu             sync.RWMutex
+	serverMu            sync.Mutex
+	server              *http.Server
+	bus                 *Bus
+	seq                 atomic.Uint64
+	receivedAt          time.Time
+	hub                 *hub
+	// mirvKills is set once DigestMIRV has published a Kill, after which
+	// kills are no longer inferred from GSI
+	mirvKills bool
 }
 
 func New(config Config) *CS2GSI {
@@ -41,7 +56,12 @@ func New(config Config) *CS2GSI {
 	logger := slog.New(logHandler)
 	slog.SetDefault(logger)
 
-	return &CS2GSI{
+	bus := config.Bus
+	if bus == nil {
+		bus = DefaultBus
+	}
+
+	gsi := &CS2GSI{
 		config:              config,
 		logger:              logger,
 		regulationMaxRounds: config.RegulationMaxRounds,
@@ -57,5 +77,15 @@ func New(config Config) *CS2GSI {
 		},
 		current: nil,
 		last:    nil,
+		bus:     bus,
 	}
+	gsi.hub = newHub(gsi)
+	gsi.restoreSessionFile()
+
+	return gsi
+}
+
+// Bus returns the event bus this instance publishes to
+func (gsi *CS2GSI) Bus() *Bus {
+	return gsi.bus
 }
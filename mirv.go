@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	models "github.com/nescabir/go-cs2-gsi/models"
 	rawModels "github.com/nescabir/go-cs2-gsi/raw"
@@ -25,6 +26,12 @@ type MIRVResult struct {
 }
 
 func (gsi *CS2GSI) DigestMIRV(raw []byte, eventType string) (*MIRVResult, error) {
+	gsi.digestMu.Lock()
+	defer gsi.digestMu.Unlock()
+
+	gsi.receivedAt = time.Now()
+	gsi.publishRawMIRV(&models.MIRVPayload{Type: eventType, Payload: raw})
+
 	if gsi.last == nil {
 		return nil, ErrMIRVNoPriorState
 	}
@@ -40,7 +47,8 @@ func (gsi *CS2GSI) DigestMIRV(raw []byte, eventType string) (*MIRVResult, error)
 			return nil, err
 		}
 		if kill != nil {
-			publishKill(kill)
+			gsi.mirvKills = true
+			gsi.publishKill(kill)
 		}
 		return &MIRVResult{Kill: kill}, nil
 	case MIRVEventPlayerHurt:
@@ -53,7 +61,7 @@ func (gsi *CS2GSI) DigestMIRV(raw []byte, eventType string) (*MIRVResult, error)
 			return nil, err
 		}
 		if hurt != nil {
-			publishHurt(hurt)
+			gsi.publishHurt(hurt)
 		}
 		return &MIRVResult{Hurt: hurt}, nil
 	default:
@@ -115,6 +123,17 @@ func (gsi *CS2GSI) digestMIRVHurt(raw *rawModels.RawHurt) (*models.HurtEvent, er
 	}, nil
 }
 
+// mirvEventName extracts the event type from the "name" field of a MIRV payload
+func mirvEventName(raw []byte) string {
+	var envelope struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return ""
+	}
+	return envelope.Name
+}
+
 func (gsi *CS2GSI) findPlayerBySteamID(steamID string) *models.Player {
 	if gsi.last == nil || steamID == "" {
 		return nil
@@ -0,0 +1,253 @@
+package models
+
+// Clone returns a deep copy of the state. Pointers shared inside the state,
+// such as a player's Team and Map.Team_ct, stay shared in the copy.
+func (s *State) Clone() *State {
+	if s == nil {
+		return nil
+	}
+	c := newCloner()
+	return &State{
+		Provider:         c.provider(s.Provider),
+		Map:              c.mapInfo(s.Map),
+		Round:            c.round(s.Round),
+		Player:           c.player(s.Player),
+		Observer:         c.observer(s.Observer),
+		AllPlayers:       c.players(s.AllPlayers),
+		Bomb:             c.bomb(s.Bomb),
+		Grenades:         c.grenades(s.Grenades),
+		Previously:       c.delta(s.Previously),
+		Added:            c.delta(s.Added),
+		Phase_countdowns: c.phaseCountdown(s.Phase_countdowns),
+		Auth:             c.auth(s.Auth),
+		Damage:           cloneDamage(s.Damage),
+		Economy:          c.economy(s.Economy),
+	}
+}
+
+// cloner copies a state graph, memoizing teams and players so that aliases
+// in the original are preserved in the copy
+type cloner struct {
+	seenTeams   map[*Team]*Team
+	seenPlayers map[*Player]*Player
+}
+
+func newCloner() *cloner {
+	return &cloner{
+		seenTeams:   make(map[*Team]*Team),
+		seenPlayers: make(map[*Player]*Player),
+	}
+}
+
+func (c *cloner) provider(p *Provider) *Provider {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	return &out
+}
+
+func (c *cloner) mapInfo(m *Map) *Map {
+	if m == nil {
+		return nil
+	}
+	out := *m
+	out.Team_ct = c.team(m.Team_ct)
+	out.Team_t = c.team(m.Team_t)
+	if m.Round_wins != nil {
+		out.Round_wins = make(map[string]RoundOutcome, len(m.Round_wins))
+		for k, v := range m.Round_wins {
+			out.Round_wins[k] = v
+		}
+	}
+	if m.Rounds != nil {
+		out.Rounds = make([]RoundInfo, len(m.Rounds))
+		for i, r := range m.Rounds {
+			r.Team = c.team(r.Team)
+			out.Rounds[i] = r
+		}
+	}
+	return &out
+}
+
+func (c *cloner) round(r *Round) *Round {
+	if r == nil {
+		return nil
+	}
+	out := *r
+	return &out
+}
+
+func (c *cloner) team(t *Team) *Team {
+	if t == nil {
+		return nil
+	}
+	if seen, ok := c.seenTeams[t]; ok {
+		return seen
+	}
+	out := *t
+	out.Extra = cloneStrings(t.Extra)
+	c.seenTeams[t] = &out
+	return &out
+}
+
+func (c *cloner) player(p *Player) *Player {
+	if p == nil {
+		return nil
+	}
+	if seen, ok := c.seenPlayers[p]; ok {
+		return seen
+	}
+	out := *p
+	c.seenPlayers[p] = &out
+	out.Team = c.team(p.Team)
+	if p.State != nil {
+		state := *p.State
+		out.State = &state
+	}
+	if p.Match_stats != nil {
+		stats := *p.Match_stats
+		out.Match_stats = &stats
+	}
+	if p.Weapons != nil {
+		out.Weapons = make(map[string]*Weapon, len(p.Weapons))
+		for k, w := range p.Weapons {
+			out.Weapons[k] = cloneWeapon(w)
+		}
+	}
+	out.Extra = cloneStrings(p.Extra)
+	return &out
+}
+
+func (c *cloner) players(players map[string]*Player) map[string]*Player {
+	if players == nil {
+		return nil
+	}
+	out := make(map[string]*Player, len(players))
+	for k, p := range players {
+		out[k] = c.player(p)
+	}
+	return out
+}
+
+func (c *cloner) observer(o *Observer) *Observer {
+	if o == nil {
+		return nil
+	}
+	out := *o
+	return &out
+}
+
+func (c *cloner) bomb(b *Bomb) *Bomb {
+	if b == nil {
+		return nil
+	}
+	out := *b
+	out.Player = c.player(b.Player)
+	return &out
+}
+
+func (c *cloner) grenades(grenades map[string]*Grenade) map[string]*Grenade {
+	if grenades == nil {
+		return nil
+	}
+	out := make(map[string]*Grenade, len(grenades))
+	for k, g := range grenades {
+		if g == nil {
+			out[k] = nil
+			continue
+		}
+		grenade := *g
+		if g.Flames != nil {
+			grenade.Flames = append([][3]float32(nil), g.Flames...)
+		}
+		out[k] = &grenade
+	}
+	return out
+}
+
+func (c *cloner) delta(d *StateDelta) *StateDelta {
+	if d == nil {
+		return nil
+	}
+	return &StateDelta{
+		Player:           c.player(d.Player),
+		AllPlayers:       c.players(d.AllPlayers),
+		Bomb:             c.bomb(d.Bomb),
+		Round:            c.round(d.Round),
+		Grenades:         c.grenades(d.Grenades),
+		Phase_countdowns: c.phaseCountdown(d.Phase_countdowns),
+	}
+}
+
+func (c *cloner) phaseCountdown(p *PhaseCountdown) *PhaseCountdown {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	out.Timeout_team = c.team(p.Timeout_team)
+	return &out
+}
+
+func (c *cloner) auth(a *Auth) *Auth {
+	if a == nil {
+		return nil
+	}
+	out := *a
+	return &out
+}
+
+func (c *cloner) economy(e *Economy) *Economy {
+	if e == nil {
+		return nil
+	}
+	out := *e
+	out.CT = c.economyForecast(e.CT)
+	out.T = c.economyForecast(e.T)
+	return &out
+}
+
+func (c *cloner) economyForecast(f *EconomyForecast) *EconomyForecast {
+	if f == nil {
+		return nil
+	}
+	out := *f
+	out.Team = c.team(f.Team)
+	if f.Players != nil {
+		out.Players = append([]PlayerForecast(nil), f.Players...)
+	}
+	return &out
+}
+
+func cloneWeapon(w *Weapon) *Weapon {
+	if w == nil {
+		return nil
+	}
+	out := *w
+	return &out
+}
+
+func cloneStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func cloneDamage(damage []RoundDamage) []RoundDamage {
+	if damage == nil {
+		return nil
+	}
+	out := make([]RoundDamage, len(damage))
+	for i, d := range damage {
+		out[i] = RoundDamage{
+			Round:   d.Round,
+			Players: append([]RoundPlayerDamage(nil), d.Players...),
+		}
+	}
+	return out
+}
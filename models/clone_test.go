@@ -0,0 +1,56 @@
+package models
+
+import "testing"
+
+func TestStateClone(t *testing.T) {
+	ct := &Team{Name: "CT", Side: CTSide, Score: 3, Extra: map[string]string{"k": "v"}}
+	player := &Player{
+		SteamId: "1",
+		Team:    ct,
+		State:   &PlayerState{Health: 100},
+		Weapons: map[string]*Weapon{"weapon_ak47": {Name: "weapon_ak47"}},
+	}
+	state := &State{
+		Map:        &Map{Name: "de_mirage", Team_ct: ct, Rounds: []RoundInfo{{Team: ct, Round: 1}}},
+		Player:     player,
+		AllPlayers: map[string]*Player{"1": player},
+		Damage:     []RoundDamage{{Round: 1, Players: []RoundPlayerDamage{{SteamId: "1", Damage: 50}}}},
+		Economy: &Economy{CT: &EconomyForecast{
+			Team:    ct,
+			Players: []PlayerForecast{{SteamId: "1", Money: 800, IfWin: 4050}},
+		}},
+	}
+
+	clone := state.Clone()
+
+	if clone.AllPlayers["1"] == player || clone.Map.Team_ct == ct {
+		t.Fatal("clone shares pointers with the original")
+	}
+	if clone.Player != clone.AllPlayers["1"] {
+		t.Fatal("observed player no longer aliases its AllPlayers entry")
+	}
+	if clone.Economy == state.Economy || clone.Economy.CT == state.Economy.CT {
+		t.Fatal("clone shares the economy forecast with the original")
+	}
+	if clone.AllPlayers["1"].Team != clone.Map.Team_ct || clone.Map.Rounds[0].Team != clone.Map.Team_ct || clone.Economy.CT.Team != clone.Map.Team_ct {
+		t.Fatal("team aliases were not preserved")
+	}
+
+	clone.AllPlayers["1"].State.Health = 0
+	clone.AllPlayers["1"].Weapons["weapon_ak47"].Name = "changed"
+	clone.Map.Team_ct.Extra["k"] = "changed"
+	clone.Damage[0].Players[0].Damage = 0
+	clone.Economy.CT.Players[0].IfWin = 0
+
+	if player.State.Health != 100 || player.Weapons["weapon_ak47"].Name != "weapon_ak47" {
+		t.Fatal("mutating the clone changed the original player")
+	}
+	if ct.Extra["k"] != "v" || state.Damage[0].Players[0].Damage != 50 || state.Economy.CT.Players[0].IfWin != 4050 {
+		t.Fatal("mutating the clone changed the original state")
+	}
+
+	var nilState *State
+	if nilState.Clone() != nil {
+		t.Fatal("nil state clone should be nil")
+	}
+}
@@ -0,0 +1,25 @@
+// Command gen writes the JSON Schema of the models package.
+//
+//	go run ./internal/schemagen/gen -o schema.json
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/nescabir/go-cs2-gsi/models/internal/schemagen"
+)
+
+func main() {
+	out := flag.String("o", "schema.json", "output file")
+	flag.Parse()
+
+	data, err := schemagen.Generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,229 @@
+// Package schemagen derives the JSON Schema of the models package from the
+// Go types and their json tags.
+package schemagen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// roots are the types published by the library. State is the document root,
+// the rest are event payloads reachable through $defs.
+var roots = []reflect.Type{
+	reflect.TypeFor[models.State](),
+	reflect.TypeFor[models.Score](),
+	reflect.TypeFor[models.KillEvent](),
+	reflect.TypeFor[models.HurtEvent](),
+	reflect.TypeFor[models.InferredKillEvent](),
+	reflect.TypeFor[models.InferredHurtEvent](),
+	reflect.TypeFor[models.WeaponEvent](),
+	reflect.TypeFor[models.BuySummaryEvent](),
+	reflect.TypeFor[models.PlayerExtension](),
+	reflect.TypeFor[models.TeamExtension](),
+}
+
+// enums lists the known values of the string types. The empty string is
+// always allowed since fields the game omits decode to it.
+var enums = map[reflect.Type][]string{
+	reflect.TypeFor[models.Side]():           {string(models.TSide), string(models.CTSide)},
+	reflect.TypeFor[models.Orientation]():    {string(models.OrientationLeft), string(models.OrientationRight)},
+	reflect.TypeFor[models.PlayerActivity](): {string(models.PlayerActivityActive), string(models.PlayerActivityMenu), string(models.PlayerActivityTextInput)},
+	reflect.TypeFor[models.MapPhase]():       {string(models.MapPhaseWarmup), string(models.MapPhaseLive), string(models.MapPhaseIntermission), string(models.MapPhaseGameOver)},
+	reflect.TypeFor[models.RoundOutcome]():   {string(models.CTWinElimination), string(models.TWinElimination), string(models.CTWinTimeLimit), string(models.CTWinDefuse), string(models.TWinBomb)},
+	reflect.TypeFor[models.BombRoundState](): {string(models.BombRoundStatePlanted), string(models.BombRoundStateExploded), string(models.BombRoundStateDefused)},
+	reflect.TypeFor[models.RoundPhase]():     {string(models.RoundPhaseFreezeTime), string(models.RoundPhaseLive), string(models.RoundPhaseOver)},
+	reflect.TypeFor[models.BombState]():      {string(models.BombStateCarried), string(models.BombStateDropped), string(models.BombStatePlanted), string(models.BombStateExploded), string(models.BombStateDefused), string(models.BombStateDefusing), string(models.BombStatePlanting)},
+	reflect.TypeFor[models.BombSite]():       {string(models.BombSiteA), string(models.BombSiteB)},
+	reflect.TypeFor[models.PhaseType]():      {string(models.PhaseTypeFreezetime), string(models.PhaseTypeBomb), string(models.PhaseTypeWarmup), string(models.PhaseTypeLive), string(models.PhaseTypeOver), string(models.PhaseTypeDefuse), string(models.PhaseTypePaused), string(models.PhaseTypeTimeoutCT), string(models.PhaseTypeTimeoutT)},
+	reflect.TypeFor[models.GrenadeType]():    {string(models.GrenadeTypeFlash), string(models.GrenadeTypeDecoy), string(models.GrenadeTypeFrag), string(models.GrenadeTypeSmoke), string(models.GrenadeTypeMolotov), string(models.GrenadeTypeIncendiary)},
+	reflect.TypeFor[models.WeaponState]():    {string(models.WeaponStateActive), string(models.WeaponStateHolstered), string(models.WeaponStateReloading)},
+	reflect.TypeFor[models.WeaponType]():     {string(models.WeaponTypeKnife), string(models.WeaponTypePistol), string(models.WeaponTypeGrenade), string(models.WeaponTypeRifle), string(models.WeaponTypeSniperRifle), string(models.WeaponTypeC4), string(models.WeaponTypeSubmachineGun), string(models.WeaponTypeShotgun), string(models.WeaponTypeMachineGun)},
+}
+
+// schema is an ordered JSON object. Keys are emitted in insertion order so
+// the generated file diffs cleanly.
+type schema struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newSchema() *schema {
+	return &schema{values: make(map[string]interface{})}
+}
+
+func (s *schema) set(key string, value interface{}) *schema {
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+	return s
+}
+
+func (s *schema) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range s.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(s.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type generator struct {
+	defs map[string]*schema
+}
+
+// Generate returns the indented JSON Schema document for the models package
+func Generate() ([]byte, error) {
+	g := &generator{defs: make(map[string]*schema)}
+	for _, t := range roots {
+		if _, err := g.typeSchema(t); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(g.defs))
+	for name := range g.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	defs := newSchema()
+	for _, name := range names {
+		defs.set(name, g.defs[name])
+	}
+
+	doc := newSchema().
+		set("$schema", draft).
+		set("title", "go-cs2-gsi models").
+		set("description", "JSON shape of the models package, schema version "+models.SchemaVersion+". Generated by go generate ./models; do not edit.").
+		set("$ref", "#/$defs/"+roots[0].Name()).
+		set("$defs", defs)
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func ref(t reflect.Type) *schema {
+	return newSchema().set("$ref", "#/$defs/"+t.Name())
+}
+
+// nullable allows null alongside the given schema
+func nullable(s *schema) *schema {
+	if typ, ok := s.values["type"].(string); ok && len(s.keys) == 1 {
+		return s.set("type", []string{typ, "null"})
+	}
+	return newSchema().set("anyOf", []*schema{s, newSchema().set("type", "null")})
+}
+
+func (g *generator) typeSchema(t reflect.Type) (*schema, error) {
+	if values, ok := enums[t]; ok {
+		return newSchema().set("type", "string").set("enum", append([]string{""}, values...)), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(elem), nil
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			if err := g.define(t); err != nil {
+				return nil, err
+			}
+		}
+		return ref(t), nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("schemagen: unsupported map key in %s", t)
+		}
+		elem, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(newSchema().set("type", "object").set("additionalProperties", elem)), nil
+	case reflect.Slice:
+		elem, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(newSchema().set("type", "array").set("items", elem)), nil
+	case reflect.Array:
+		elem, err := g.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return newSchema().set("type", "array").set("items", elem).
+			set("minItems", t.Len()).set("maxItems", t.Len()), nil
+	case reflect.String:
+		return newSchema().set("type", "string"), nil
+	case reflect.Bool:
+		return newSchema().set("type", "boolean"), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return newSchema().set("type", "integer"), nil
+	case reflect.Float32, reflect.Float64:
+		return newSchema().set("type", "number"), nil
+	}
+	return nil, fmt.Errorf("schemagen: unsupported type %s", t)
+}
+
+// define adds a struct to $defs. The placeholder is stored first so that
+// recursive types terminate.
+func (g *generator) define(t reflect.Type) error {
+	def := newSchema().set("type", "object")
+	g.defs[t.Name()] = def
+
+	properties := newSchema()
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			return fmt.Errorf("schemagen: %s.%s has no json tag", t.Name(), field.Name)
+		}
+
+		fieldSchema, err := g.typeSchema(field.Type)
+		if err != nil {
+			return err
+		}
+		properties.set(name, fieldSchema)
+		required = append(required, name)
+	}
+
+	def.set("properties", properties).
+		set("required", required).
+		set("additionalProperties", false)
+	return nil
+}
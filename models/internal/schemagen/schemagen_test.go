@@ -0,0 +1,22 @@
+package schemagen
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestSchemaUpToDate(t *testing.T) {
+	want, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	got, err := os.ReadFile("../../schema.json")
+	if err != nil {
+		t.Fatalf("read schema.json: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("models/schema.json is stale, run go generate ./models")
+	}
+}
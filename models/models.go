@@ -101,6 +101,16 @@ const (
 	GrenadeTypeIncendiary GrenadeType = "inferno"
 )
 
+type BuyType string
+
+const (
+	BuyTypePistol  BuyType = "pistol"
+	BuyTypeEco     BuyType = "eco"
+	BuyTypeForce   BuyType = "force"
+	BuyTypeHalfBuy BuyType = "halfBuy"
+	BuyTypeFullBuy BuyType = "fullBuy"
+)
+
 type WeaponState string
 
 const (
@@ -124,105 +134,107 @@ const (
 )
 
 type State struct {
-	Provider         *Provider
-	Map              *Map
-	Round            *Round
-	Player           *Player
-	Observer         *Observer
-	AllPlayers       map[string]*Player // allplayers_*: steamid64 ...
-	Bomb             *Bomb
-	Grenades         map[string]*Grenade
-	Previously       *StateDelta
-	Added            *StateDelta
-	Phase_countdowns *PhaseCountdown
-	Auth             *Auth
-	Damage           []RoundDamage
+	Provider         *Provider           `json:"provider"`
+	Map              *Map                `json:"map"`
+	Round            *Round              `json:"round"`
+	Player           *Player             `json:"player"`
+	Observer         *Observer           `json:"observer"`
+	AllPlayers       map[string]*Player  `json:"allPlayers"` // allplayers_*: steamid64 ...
+	Bomb             *Bomb               `json:"bomb"`
+	Grenades         map[string]*Grenade `json:"grenades"`
+	Previously       *StateDelta         `json:"previously"`
+	Added            *StateDelta         `json:"added"`
+	Phase_countdowns *PhaseCountdown     `json:"phaseCountdowns"`
+	Auth             *Auth               `json:"-"`
+	Damage           []RoundDamage       `json:"damage"`
+	// Economy is the money outlook for the next round, nil during warmup
+	Economy *Economy `json:"economy"`
 }
 
 // StateDelta is a shallow parsed GSI delta (previously / added blocks).
 type StateDelta struct {
-	Player           *Player
-	AllPlayers       map[string]*Player
-	Bomb             *Bomb
-	Round            *Round
-	Grenades         map[string]*Grenade
-	Phase_countdowns *PhaseCountdown
+	Player           *Player             `json:"player"`
+	AllPlayers       map[string]*Player  `json:"allPlayers"`
+	Bomb             *Bomb               `json:"bomb"`
+	Round            *Round              `json:"round"`
+	Grenades         map[string]*Grenade `json:"grenades"`
+	Phase_countdowns *PhaseCountdown     `json:"phaseCountdowns"`
 }
 
 type PlayerExtension struct {
-	SteamId  string
-	Name     string
-	Avatar   string
-	Country  string
-	RealName string
-	Extra    map[string]string
+	SteamId  string            `json:"steamId"`
+	Name     string            `json:"name"`
+	Avatar   string            `json:"avatar"`
+	Country  string            `json:"country"`
+	RealName string            `json:"realName"`
+	Extra    map[string]string `json:"extra"`
 }
 
 type TeamExtension struct {
-	Id       string
-	Logo     string
-	Country  string
-	Name     string
-	MapScore int
-	Extra    map[string]string
+	Id       string            `json:"id"`
+	Logo     string            `json:"logo"`
+	Country  string            `json:"country"`
+	Name     string            `json:"name"`
+	MapScore int               `json:"mapScore"`
+	Extra    map[string]string `json:"extra"`
 }
 
 // provider
 type Provider struct {
-	Name      string
-	AppId     int
-	Version   int
-	SteamId   string
-	Timestamp float32
+	Name      string  `json:"name"`
+	AppId     int     `json:"appId"`
+	Version   int     `json:"version"`
+	SteamId   string  `json:"steamId"`
+	Timestamp float32 `json:"timestamp"`
 }
 
 // map
 type Map struct {
-	Mode                      string
-	Name                      string
-	Phase                     MapPhase
-	Round                     int
-	Team_ct                   *Team
-	Team_t                    *Team
-	Num_matches_to_win_series int
-	Current_spectators        int
-	Souvenirs_total           int
-	Round_wins                map[string]RoundOutcome
-	Rounds                    []RoundInfo
+	Mode                      string                  `json:"mode"`
+	Name                      string                  `json:"name"`
+	Phase                     MapPhase                `json:"phase"`
+	Round                     int                     `json:"round"`
+	Team_ct                   *Team                   `json:"teamCT"`
+	Team_t                    *Team                   `json:"teamT"`
+	Num_matches_to_win_series int                     `json:"numMatchesToWinSeries"`
+	Current_spectators        int                     `json:"currentSpectators"`
+	Souvenirs_total           int                     `json:"souvenirsTotal"`
+	Round_wins                map[string]RoundOutcome `json:"roundWins"`
+	Rounds                    []RoundInfo             `json:"rounds"`
 }
 
 // round
 type Round struct {
-	Phase    RoundPhase
-	Win_team Side
-	Bomb     BombRoundState
+	Phase    RoundPhase     `json:"phase"`
+	Win_team Side           `json:"winTeam"`
+	Bomb     BombRoundState `json:"bomb"`
 }
 
 type RoundInfo struct {
-	Team    *Team
-	Round   int
-	Side    Side
-	Outcome RoundOutcome
+	Team    *Team        `json:"team"`
+	Round   int          `json:"round"`
+	Side    Side         `json:"side"`
+	Outcome RoundOutcome `json:"outcome"`
 }
 
 // player_id
 type Player struct {
-	SteamId       string
-	Clan          string
-	Name          string
-	DefaultName   string
-	Observer_slot int
-	Team          *Team
-	Activity      PlayerActivity
-	State         *PlayerState
-	Weapons       map[string]*Weapon
-	Match_stats   *PlayerMatchStats
-	Position      [3]float32
-	Forward       [3]float32
-	Avatar        string
-	Country       string
-	RealName      string
-	Extra         map[string]string
+	SteamId       string             `json:"steamId"`
+	Clan          string             `json:"clan"`
+	Name          string             `json:"name"`
+	DefaultName   string             `json:"defaultName"`
+	Observer_slot int                `json:"observerSlot"`
+	Team          *Team              `json:"team"`
+	Activity      PlayerActivity     `json:"activity"`
+	State         *PlayerState       `json:"state"`
+	Weapons       map[string]*Weapon `json:"weapons"`
+	Match_stats   *PlayerMatchStats  `json:"matchStats"`
+	Position      [3]float32         `json:"position"`
+	Forward       [3]float32         `json:"forward"`
+	Avatar        string             `json:"avatar"`
+	Country       string             `json:"country"`
+	RealName      string             `json:"realName"`
+	Extra         map[string]string  `json:"extra"`
 }
 
 func (p *Player) IsAlive() bool {
@@ -230,144 +242,249 @@ func (p *Player) IsAlive() bool {
 }
 
 type Observer struct {
-	Activity   PlayerActivity
-	Spectarget string
-	Position   [3]float32
-	Forward    [3]float32
+	Activity   PlayerActivity `json:"activity"`
+	Spectarget string         `json:"specTarget"`
+	Position   [3]float32     `json:"position"`
+	Forward    [3]float32     `json:"forward"`
 }
 
 // team
 type Team struct {
-	Logo                     string
-	Score                    int
-	Consecutive_round_losses int
-	Timeouts_remaining       int
-	Matches_won_this_series  int
-	Name                     string
-	Flag                     string
-	Side                     Side
-	Orientation              Orientation
-	Id                       string
-	Country                  string
-	Extra                    map[string]string
+	Logo                     string            `json:"logo"`
+	Score                    int               `json:"score"`
+	Consecutive_round_losses int               `json:"consecutiveRoundLosses"`
+	Timeouts_remaining       int               `json:"timeoutsRemaining"`
+	Matches_won_this_series  int               `json:"matchesWonThisSeries"`
+	Name                     string            `json:"name"`
+	Flag                     string            `json:"flag"`
+	Side                     Side              `json:"side"`
+	Orientation              Orientation       `json:"orientation"`
+	Id                       string            `json:"id"`
+	Country                  string            `json:"country"`
+	Extra                    map[string]string `json:"extra"`
 }
 
 // player_state
 type PlayerState struct {
-	Health         int
-	Armor          int
-	Helmet         bool
-	DefuseKit      bool
-	Flashed        int
-	Smoked         int
-	Burning        int
-	Money          int
-	Round_kills    int
-	Round_killhs   int
-	Round_totaldmg int
-	Equip_value    int
-	Adr            int
+	Health         int  `json:"health"`
+	Armor          int  `json:"armor"`
+	Helmet         bool `json:"helmet"`
+	DefuseKit      bool `json:"defuseKit"`
+	Flashed        int  `json:"flashed"`
+	Smoked         int  `json:"smoked"`
+	Burning        int  `json:"burning"`
+	Money          int  `json:"money"`
+	Round_kills    int  `json:"roundKills"`
+	Round_killhs   int  `json:"roundKillHs"`
+	Round_totaldmg int  `json:"roundTotalDmg"`
+	Equip_value    int  `json:"equipValue"`
+	Adr            int  `json:"adr"`
 }
 
 // player_weapons: weapon_0, weapon_1, weapon_2 ...
 type Weapon struct {
-	Name          string
-	PaintKit      string
-	Type          WeaponType
-	State         WeaponState
-	Ammo_clip     int
-	Ammo_clip_max int
-	Ammo_reserve  int
+	Name          string      `json:"name"`
+	PaintKit      string      `json:"paintKit"`
+	Type          WeaponType  `json:"type"`
+	State         WeaponState `json:"state"`
+	Ammo_clip     int         `json:"ammoClip"`
+	Ammo_clip_max int         `json:"ammoClipMax"`
+	Ammo_reserve  int         `json:"ammoReserve"`
 }
 
 // player_match_stats
 type PlayerMatchStats struct {
-	Kills   int
-	Assists int
-	Deaths  int
-	Mvps    int
-	Score   int
+	Kills   int `json:"kills"`
+	Assists int `json:"assists"`
+	Deaths  int `json:"deaths"`
+	Mvps    int `json:"mvps"`
+	Score   int `json:"score"`
 }
 
 type Auth struct {
-	Token string
+	Token string `json:"token"`
 }
 
 type Bomb struct {
-	State     BombState
-	Countdown float32
-	Player    *Player
-	Position  [3]float32
-	Site      BombSite
+	State     BombState  `json:"state"`
+	Countdown float32    `json:"countdown"`
+	Player    *Player    `json:"player"`
+	Position  [3]float32 `json:"position"`
+	Site      BombSite   `json:"site"`
 }
 
 type PhaseCountdown struct {
-	Phase         PhaseType
-	Phase_ends_in float32
-	Timeout_team  *Team
+	Phase         PhaseType `json:"phase"`
+	Phase_ends_in float32   `json:"phaseEndsIn"`
+	Timeout_team  *Team     `json:"timeoutTeam"`
 }
 
 type Grenade struct {
-	ID         string
-	Owner      string
-	Position   [3]float32
-	Velocity   [3]float32
-	Type       GrenadeType
-	Lifetime   float32
-	EffectTime float32
-	Flames     [][3]float32
+	ID         string       `json:"id"`
+	Owner      string       `json:"owner"`
+	Position   [3]float32   `json:"position"`
+	Velocity   [3]float32   `json:"velocity"`
+	Type       GrenadeType  `json:"type"`
+	Lifetime   float32      `json:"lifetime"`
+	EffectTime float32      `json:"effectTime"`
+	Flames     [][3]float32 `json:"flames"`
 }
 
 type RoundPlayerDamage struct {
-	SteamId string
-	Damage  int
+	SteamId string `json:"steamId"`
+	Damage  int    `json:"damage"`
 }
 
 type RoundDamage struct {
-	Round   int
-	Players []RoundPlayerDamage
+	Round   int                 `json:"round"`
+	Players []RoundPlayerDamage `json:"players"`
 }
 
 type Score struct {
-	Winner *Team
-	Loser  *Team
-	Map    *Map
-	MapEnd bool
+	Winner *Team `json:"winner"`
+	Loser  *Team `json:"loser"`
+	Map    *Map  `json:"map"`
+	MapEnd bool  `json:"mapEnd"`
+	// Economy is the money each player takes into the next round
+	Economy *Economy `json:"economy"`
 }
 
 type KillEvent struct {
-	Attacker      *Player
-	Victim        *Player
-	Weapon        *Weapon
-	Assister      *Player
-	Flashed       bool
-	Headshot      bool
-	Wallbang      bool
-	AttackerBlind bool
-	ThruSmoke     bool
-	NoScope       bool
-	AttackerInAir bool
+	Attacker      *Player `json:"attacker"`
+	Victim        *Player `json:"victim"`
+	Weapon        *Weapon `json:"weapon"`
+	Assister      *Player `json:"assister"`
+	Flashed       bool    `json:"flashed"`
+	Headshot      bool    `json:"headshot"`
+	Wallbang      bool    `json:"wallbang"`
+	AttackerBlind bool    `json:"attackerBlind"`
+	ThruSmoke     bool    `json:"thruSmoke"`
+	NoScope       bool    `json:"noScope"`
+	AttackerInAir bool    `json:"attackerInAir"`
 }
 
 type HurtEvent struct {
-	Attacker  *Player
-	Victim    *Player
-	Weapon    *Weapon
-	Health    int
-	Armor     int
-	DmgHealth int
-	DmgArmor  int
-	HitGroup  int
+	Attacker  *Player `json:"attacker"`
+	Victim    *Player `json:"victim"`
+	Weapon    *Weapon `json:"weapon"`
+	Health    int     `json:"health"`
+	Armor     int     `json:"armor"`
+	DmgHealth int     `json:"dmgHealth"`
+	DmgArmor  int     `json:"dmgArmor"`
+	HitGroup  int     `json:"hitGroup"`
+}
+
+// InferredKillEvent is a kill reconstructed from GSI alone, for setups
+// without HLAE. Attacker is nil when no enemy's kill counters moved.
+type InferredKillEvent struct {
+	Attacker *Player `json:"attacker"`
+	Victim   *Player `json:"victim"`
+	// Weapon is the attacker's active weapon
+	Weapon   *Weapon `json:"weapon"`
+	Headshot bool    `json:"headshot"`
+	// Confidence is how certain the attribution is, from 0 (unknown
+	// attacker) to 1 (a single enemy whose kill count went up)
+	Confidence float64 `json:"confidence"`
+}
+
+// InferredHurtEvent is damage taken reconstructed from GSI health and armor
+// deltas, for setups without HLAE. Attacker is nil when no enemy's damage
+// counter moved, e.g. for fall, bomb or self-inflicted damage.
+type InferredHurtEvent struct {
+	Attacker *Player `json:"attacker"`
+	Victim   *Player `json:"victim"`
+	// Weapon is the attacker's active weapon
+	Weapon    *Weapon `json:"weapon"`
+	Health    int     `json:"health"`
+	Armor     int     `json:"armor"`
+	DmgHealth int     `json:"dmgHealth"`
+	DmgArmor  int     `json:"dmgArmor"`
+	// Confidence is how certain the attribution is, from 0 (unknown
+	// attacker) to 1 (a single enemy whose damage matches)
+	Confidence float64 `json:"confidence"`
+}
+
+// WeaponEvent is a change in a player's inventory
+type WeaponEvent struct {
+	Player *Player `json:"player"`
+	Weapon *Weapon `json:"weapon"`
+	// Previous is the weapon held before a WeaponSwitch, nil for other events
+	Previous *Weapon `json:"previous"`
+}
+
+// BuySummaryEvent is what a team bought for the round, taken at freezetime end
+type BuySummaryEvent struct {
+	Team    *Team `json:"team"`
+	Players int   `json:"players"`
+	// Money is the team's money left after buying
+	Money      int `json:"money"`
+	EquipValue int `json:"equipValue"`
+	Armor      int `json:"armor"`
+	Helmets    int `json:"helmets"`
+	DefuseKits int `json:"defuseKits"`
+	// Primaries are the names of the primary weapons carried, sorted
+	Primaries []string `json:"primaries"`
+	Type      BuyType  `json:"type"`
+}
+
+// Economy is the money outlook of both teams for the next round
+type Economy struct {
+	CT *EconomyForecast `json:"ct"`
+	T  *EconomyForecast `json:"t"`
+	// Outcome is how the round was decided, empty while it is played
+	Outcome RoundOutcome `json:"outcome"`
+}
+
+// EconomyForecast is a team's money outlook for the next round
+type EconomyForecast struct {
+	Team *Team `json:"team"`
+	// LossBonusTier is the loss bonus level reached by losing the round,
+	// from 1 to 5
+	LossBonusTier int `json:"lossBonusTier"`
+	// LossBonus is paid to each player when the team loses the round
+	LossBonus int `json:"lossBonus"`
+	// MinimumMoney is the least any player of the team has next round
+	MinimumMoney int              `json:"minimumMoney"`
+	Players      []PlayerForecast `json:"players"`
+}
+
+// PlayerForecast is a player's money for the next round
+type PlayerForecast struct {
+	SteamId string `json:"steamId"`
+	Money   int    `json:"money"`
+	// Minimum is the money guaranteed whatever the outcome
+	Minimum int `json:"minimum"`
+	IfWin   int `json:"ifWin"`
+	IfLoss  int `json:"ifLoss"`
+	// Expected is the money after the actual outcome, 0 until the round is
+	// decided
+	Expected int `json:"expected"`
+}
+
+// MIRVPayload is an HLAE game event as received by DigestMIRV
+type MIRVPayload struct {
+	Type    string `json:"type"`
+	Payload []byte `json:"payload"`
 }
 
 type Events string
 
 const (
 	Raw               Events = "raw"
+	RawMIRV           Events = "rawMirv"
 	Data              Events = "data"
 	RoundEnd          Events = "roundEnd"
 	Kill              Events = "kill"
 	Hurt              Events = "hurt"
+	Death             Events = "death"
+	InferredKill      Events = "inferredKill"
+	InferredHurt      Events = "inferredHurt"
+	WeaponPurchase    Events = "weaponPurchase"
+	WeaponPickup      Events = "weaponPickup"
+	WeaponDrop        Events = "weaponDrop"
+	WeaponSwitch      Events = "weaponSwitch"
+	GrenadeThrow      Events = "grenadeThrow"
+	BuySummary        Events = "buySummary"
 	TimeoutStart      Events = "timeoutStart"
 	TimeoutEnd        Events = "timeoutEnd"
 	Mvp               Events = "mvp"
@@ -0,0 +1,18 @@
+package models
+
+import _ "embed"
+
+//go:generate go run ./internal/schemagen/gen -o schema.json
+
+// SchemaVersion is the version of the JSON shape produced by marshalling the
+// types in this package. Field names come from explicit camelCase json tags
+// and do not follow Go field renames. The major version changes when a field
+// is removed, renamed or changes type; adding a field bumps the minor
+// version. State.Auth is never serialized.
+const SchemaVersion = "1.5.0"
+
+// JSONSchema is the JSON Schema (draft 2020-12) describing State and the
+// event payloads, generated from the types by go generate.
+//
+//go:embed schema.json
+var JSONSchema []byte
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStateJSONShape(t *testing.T) {
+	state := &State{
+		Map:              &Map{Name: "de_mirage", Team_ct: &Team{Consecutive_round_losses: 2}},
+		AllPlayers:       map[string]*Player{"1": {SteamId: "1", Match_stats: &PlayerMatchStats{Kills: 3}}},
+		Phase_countdowns: &PhaseCountdown{Phase: PhaseTypeLive, Phase_ends_in: 30},
+		Auth:             &Auth{Token: "secret"},
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if _, ok := doc["auth"]; ok {
+		t.Fatal("auth must not be serialized")
+	}
+	if countdowns, ok := doc["phaseCountdowns"].(map[string]interface{}); !ok || countdowns["phaseEndsIn"] != 30.0 {
+		t.Fatalf("phaseCountdowns = %v", doc["phaseCountdowns"])
+	}
+	teamCT := doc["map"].(map[string]interface{})["teamCT"].(map[string]interface{})
+	if teamCT["consecutiveRoundLosses"] != 2.0 {
+		t.Fatalf("teamCT = %v", teamCT)
+	}
+	player := doc["allPlayers"].(map[string]interface{})["1"].(map[string]interface{})
+	if player["matchStats"].(map[string]interface{})["kills"] != 3.0 {
+		t.Fatalf("player = %v", player)
+	}
+}
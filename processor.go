@@ -277,17 +277,33 @@ func (gsi *CS2GSI) calculatePlayerADR(player models.Player, currentRound int, ma
 
 // updateStateAndDetectEvents updates the current state and detects events
 func (gsi *CS2GSI) updateStateAndDetectEvents(state *models.State) error {
+	// Finish mutating the state before it becomes visible to Snapshot readers
+	gsi.applyRoundEndScore(state)
+	gsi.applyEconomy(state)
+
 	// Update current state
-	gsi.current = state
+	gsi.setCurrent(state)
 
 	// Handle first state
 	if gsi.last == nil {
 		gsi.last = state
-		publishData(state)
+		gsi.publishData(state)
 		return nil
 	}
 
 	// Detect and publish events
+	if err := gsi.detectHurtEvents(state); err != nil {
+		return err
+	}
+
+	if err := gsi.detectDeathEvents(state); err != nil {
+		return err
+	}
+
+	if err := gsi.detectWeaponEvents(state); err != nil {
+		return err
+	}
+
 	if err := gsi.detectRoundEvents(state); err != nil {
 		return err
 	}
@@ -308,10 +324,16 @@ func (gsi *CS2GSI) updateStateAndDetectEvents(state *models.State) error {
 		return err
 	}
 
+	roundEnded := isRoundEnd(gsi.last, state)
+
 	// Publish data and update last state
-	publishData(state)
+	gsi.publishData(state)
 	gsi.last = state
 
+	if roundEnded {
+		gsi.checkpoint()
+	}
+
 	return nil
 }
 
@@ -323,30 +345,48 @@ func (gsi *CS2GSI) detectRoundEvents(state *models.State) error {
 	}
 
 	// Check for round end
-	if state.Round.Win_team != "" && last.Round.Win_team == "" {
+	if isRoundEnd(last, state) {
 		winner, loser := gsi.determineWinnerAndLoser(state)
-		gsi.updateWinnerScore(winner, last)
 
 		roundScore := &models.Score{
-			Winner: winner,
-			Loser:  loser,
-			Map:    state.Map,
-			MapEnd: state.Map.Phase == models.MapPhaseGameOver,
+			Winner:  winner,
+			Loser:   loser,
+			Map:     state.Map,
+			MapEnd:  state.Map.Phase == models.MapPhaseGameOver,
+			Economy: state.Economy,
 		}
 
 		gsi.logger.Info("Round end detected", "winner", winner.Side, "loser", loser.Side, "score", fmt.Sprintf("%d-%d", winner.Score, loser.Score))
-		publishRoundEnd(roundScore)
+		gsi.publishRoundEnd(roundScore)
 
 		// Check for match end
 		if roundScore.MapEnd && last.Map.Phase != models.MapPhaseGameOver {
 			gsi.logger.Info("Match end detected", "winner", winner.Side, "loser", loser.Side, "score", fmt.Sprintf("%d-%d", winner.Score, loser.Score))
-			publishMatchEnd(roundScore)
+			gsi.publishMatchEnd(roundScore)
 		}
 	}
 
 	return nil
 }
 
+// isRoundEnd reports whether a round winner appeared between last and state
+func isRoundEnd(last, state *models.State) bool {
+	if last == nil || last.Round == nil || state.Round == nil {
+		return false
+	}
+	return state.Round.Win_team != "" && last.Round.Win_team == ""
+}
+
+// applyRoundEndScore credits the round to the winner when the score in the
+// payload has not caught up with the round end yet
+func (gsi *CS2GSI) applyRoundEndScore(state *models.State) {
+	if !isRoundEnd(gsi.last, state) {
+		return
+	}
+	winner, _ := gsi.determineWinnerAndLoser(state)
+	gsi.updateWinnerScore(winner, gsi.last)
+}
+
 // determineWinnerAndLoser determines which team won and lost
 func (gsi *CS2GSI) determineWinnerAndLoser(state *models.State) (*models.Team, *models.Team) {
 	if state.Round.Win_team == models.CTSide {
@@ -381,7 +421,7 @@ func (gsi *CS2GSI) detectBombEvents(state *models.State) error {
 		gsi.handleBombStateChanges(last.Bomb, state.Bomb)
 	} else if last.Bomb == nil && state.Bomb != nil && state.Bomb.State == models.BombStateExploded {
 		gsi.logger.Info("Bomb exploded")
-		publishBombExploded(nil)
+		gsi.publishBombExploded(nil)
 	}
 
 	return nil
@@ -395,43 +435,43 @@ func (gsi *CS2GSI) handleBombStateChanges(lastBomb, currentBomb *models.Bomb) {
 		currentBomb.State != models.BombStatePlanted &&
 		currentBomb.State != models.BombStateDefusing {
 		gsi.logger.Info("Bomb plant stop detected", "player", lastBomb.Player.Name)
-		publishBombPlantStop(lastBomb.Player)
+		gsi.publishBombPlantStop(lastBomb.Player)
 	}
 
 	// Bomb planted
 	if lastBomb.State == models.BombStatePlanting && currentBomb.State == models.BombStatePlanted {
 		gsi.logger.Info("Bomb planted", "player", lastBomb.Player.Name)
-		publishBombPlanted(lastBomb.Player)
+		gsi.publishBombPlanted(lastBomb.Player)
 	}
 
 	// Bomb exploded
 	if lastBomb.State != models.BombStateExploded && currentBomb.State == models.BombStateExploded {
 		gsi.logger.Info("Bomb exploded")
-		publishBombExploded(nil)
+		gsi.publishBombExploded(nil)
 	}
 
 	// Bomb defused
 	if lastBomb.State != models.BombStateDefused && currentBomb.State == models.BombStateDefused {
 		gsi.logger.Info("Bomb defused", "player", lastBomb.Player.Name)
-		publishBombDefused(lastBomb.Player)
+		gsi.publishBombDefused(lastBomb.Player)
 	}
 
 	// Defuse start
 	if lastBomb.State != models.BombStateDefusing && currentBomb.State == models.BombStateDefusing {
 		gsi.logger.Info("Defuse start detected", "player", currentBomb.Player.Name)
-		publishDefuseStart(currentBomb.Player)
+		gsi.publishDefuseStart(currentBomb.Player)
 	}
 
 	// Defuse end
 	if lastBomb.State == models.BombStateDefusing && currentBomb.State != models.BombStateDefusing {
 		gsi.logger.Info("Defuse end detected", "player", lastBomb.Player.Name)
-		publishDefuseEnd(lastBomb.Player)
+		gsi.publishDefuseEnd(lastBomb.Player)
 	}
 
 	// Bomb plant start
 	if lastBomb.State != models.BombStatePlanting && currentBomb.State == models.BombStatePlanting {
 		gsi.logger.Info("Bomb plant start detected", "player", currentBomb.Player.Name)
-		publishBombPlantStart(currentBomb.Player)
+		gsi.publishBombPlantStart(currentBomb.Player)
 	}
 }
 
@@ -445,20 +485,21 @@ func (gsi *CS2GSI) detectPhaseEvents(state *models.State) error {
 	// Intermission events
 	if state.Map.Phase == models.MapPhaseIntermission && last.Map.Phase != models.MapPhaseIntermission {
 		gsi.logger.Info("Intermission start detected")
-		publishIntermissionStart(nil)
+		gsi.publishIntermissionStart(nil)
 	} else if state.Map.Phase != models.MapPhaseIntermission && last.Map.Phase == models.MapPhaseIntermission {
 		gsi.logger.Info("Intermission end detected")
-		publishIntermissionEnd(nil)
+		gsi.publishIntermissionEnd(nil)
 	}
 
 	// Freezetime events
 	phase := state.Phase_countdowns.Phase
 	if phase == models.PhaseTypeFreezetime && last.Phase_countdowns.Phase != models.PhaseTypeFreezetime {
 		gsi.logger.Info("Freezetime start detected")
-		publishFreezetimeStart(nil)
+		gsi.publishFreezetimeStart(nil)
 	} else if phase != models.PhaseTypeFreezetime && last.Phase_countdowns.Phase == models.PhaseTypeFreezetime {
 		gsi.logger.Info("Freezetime end detected")
-		publishFreezetimeEnd(nil)
+		gsi.publishFreezetimeEnd(nil)
+		gsi.publishBuySummaries(state)
 	}
 
 	return nil
@@ -483,13 +524,13 @@ func (gsi *CS2GSI) detectTimeoutEvents(state *models.State) error {
 			team = gsi.teams.t
 		}
 		gsi.logger.Info("Timeout start detected", "team", team.Name, "side", team.Side)
-		publishTimeoutStart(team)
+		gsi.publishTimeoutStart(team)
 	}
 
 	// Timeout end
 	if strings.HasPrefix(string(lastPhase), "timeout") && !strings.HasPrefix(string(phase), "timeout") {
 		gsi.logger.Info("Timeout end detected")
-		publishTimeoutEnd(nil)
+		gsi.publishTimeoutEnd(nil)
 	}
 
 	return nil
@@ -507,7 +548,7 @@ func (gsi *CS2GSI) detectMVPEvents(state *models.State) error {
 		if previousPlayer, exists := last.AllPlayers[player.SteamId]; exists {
 			if player.Match_stats.Mvps > previousPlayer.Match_stats.Mvps {
 				gsi.logger.Info("MVP detected", "player", player.Name)
-				publishMvp(player)
+				gsi.publishMvp(player)
 				break
 			}
 		}
@@ -0,0 +1,160 @@
+package cs2gsi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// sessionVersion is the format written by SaveSession
+const sessionVersion = 1
+
+// ErrSessionVersion is returned by LoadSession for a session written in a
+// format this version cannot read
+var ErrSessionVersion = errors.New("unsupported session version")
+
+// session is what an instance learned from previous payloads
+type session struct {
+	Version int `json:"version"`
+	// Seq is the sequence number of the last published event
+	Seq uint64 `json:"seq"`
+	// State is the last digested state, used for edge detection
+	State   *models.State        `json:"state"`
+	Damage  []models.RoundDamage `json:"damage"`
+	Players []models.Player      `json:"players"`
+	TeamCT  *models.Team         `json:"teamCT"`
+	TeamT   *models.Team         `json:"teamT"`
+	// MapName and Payload are the delta accumulator. The auth block is left
+	// out so the token is never written to disk.
+	MapName string                     `json:"mapName"`
+	Payload map[string]json.RawMessage `json:"payload"`
+	// MIRVKills is set once DigestMIRV published a Kill, so kills are no
+	// longer inferred
+	MIRVKills bool `json:"mirvKills"`
+}
+
+// SaveSession writes what the instance learned from previous payloads: the
+// last state used for edge detection, the damage history behind ADR, the
+// delta accumulator and whether kills come from DigestMIRV. An instance restarted mid-match and given the session
+// with LoadSession resumes with the same ADR and no spurious events.
+func (gsi *CS2GSI) SaveSession(w io.Writer) error {
+	gsi.digestMu.Lock()
+	defer gsi.digestMu.Unlock()
+	return gsi.writeSession(w)
+}
+
+// LoadSession restores a session written by SaveSession, replacing what the
+// instance learned so far. Subscriptions are kept.
+func (gsi *CS2GSI) LoadSession(r io.Reader) error {
+	var s session
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("failed to decode session: %w", err)
+	}
+	if s.Version != sessionVersion {
+		return fmt.Errorf("%w: %d", ErrSessionVersion, s.Version)
+	}
+
+	gsi.digestMu.Lock()
+	defer gsi.digestMu.Unlock()
+
+	if s.State != nil && s.State.Auth == nil {
+		s.State.Auth = &models.Auth{}
+	}
+	gsi.damage = append(make([]models.RoundDamage, 0, 60), s.Damage...)
+	gsi.players = append(make([]models.Player, 0, 16), s.Players...)
+	gsi.teams = &teams{ct: s.TeamCT, t: s.TeamT}
+	gsi.payloadAcc = newPayloadAcc()
+	gsi.payloadAcc.mapName = s.MapName
+	for key, value := range s.Payload {
+		gsi.payloadAcc.keys[key] = value
+	}
+	gsi.mirvKills = s.MIRVKills
+	gsi.last = s.State
+	gsi.setCurrent(s.State)
+	if s.Seq > gsi.seq.Load() {
+		gsi.seq.Store(s.Seq)
+	}
+	return nil
+}
+
+// writeSession encodes the session; digestMu must be held
+func (gsi *CS2GSI) writeSession(w io.Writer) error {
+	s := session{
+		Version:   sessionVersion,
+		Seq:       gsi.seq.Load(),
+		State:     gsi.last,
+		Damage:    gsi.damage,
+		Players:   gsi.players,
+		TeamCT:    gsi.teams.ct,
+		TeamT:     gsi.teams.t,
+		MIRVKills: gsi.mirvKills,
+	}
+
+	gsi.payloadAcc.mu.Lock()
+	s.MapName = gsi.payloadAcc.mapName
+	s.Payload = make(map[string]json.RawMessage, len(gsi.payloadAcc.keys))
+	for key, value := range gsi.payloadAcc.keys {
+		if key != "auth" {
+			s.Payload[key] = value
+		}
+	}
+	gsi.payloadAcc.mu.Unlock()
+
+	return json.NewEncoder(w).Encode(&s)
+}
+
+// checkpoint saves the session to Config.SessionFile. The file is replaced
+// atomically so a crash mid-write keeps the previous checkpoint.
+func (gsi *CS2GSI) checkpoint() {
+	path := gsi.config.SessionFile
+	if path == "" {
+		return
+	}
+
+	err := func() error {
+		tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+
+		if err := gsi.writeSession(tmp); err != nil {
+			tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		return os.Rename(tmp.Name(), path)
+	}()
+	if err != nil {
+		gsi.logger.Error("failed to save session", "path", path, "error", err)
+	}
+}
+
+// restoreSessionFile loads Config.SessionFile when it exists
+func (gsi *CS2GSI) restoreSessionFile() {
+	path := gsi.config.SessionFile
+	if path == "" {
+		return
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err == nil {
+		defer file.Close()
+		err = gsi.LoadSession(file)
+	}
+	if err != nil {
+		gsi.logger.Error("failed to restore session", "path", path, "error", err)
+		return
+	}
+	gsi.logger.Info("Session restored", "path", path)
+}
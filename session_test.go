@@ -0,0 +1,147 @@
+package cs2gsi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+// readCapturePayloads returns the GSI payloads of a capture fixture
+func readCapturePayloads(t *testing.T, path string) [][]byte {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer file.Close()
+
+	var payloads [][]byte
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var entry struct {
+			Source  string          `json:"source"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decode fixture: %v", err)
+		}
+		if entry.Source == "gsi" {
+			payloads = append(payloads, entry.Payload)
+		}
+	}
+	return payloads
+}
+
+func digestAll(t *testing.T, gsi *CS2GSI, payloads [][]byte) []string {
+	t.Helper()
+	var names []string
+	sub := SubscribeAllTo(gsi, func(event GameEvent) {
+		names = append(names, event.EventName())
+	})
+	defer sub.Unsubscribe()
+	for _, payload := range payloads {
+		if err := gsi.Digest(payload); err != nil {
+			t.Fatalf("Digest: %v", err)
+		}
+	}
+	return names
+}
+
+func adr(state *models.State) map[string]int {
+	out := make(map[string]int)
+	for id, player := range state.AllPlayers {
+		out[id] = player.State.Adr
+	}
+	return out
+}
+
+func TestSessionResume(t *testing.T) {
+	payloads := readCapturePayloads(t, "testdata/golden/bomb_round/capture.ndjson")
+	split := 3
+
+	reference := New(Config{Bus: NewBus()})
+	digestAll(t, reference, payloads[:split])
+	want := digestAll(t, reference, payloads[split:])
+
+	crashed := New(Config{Bus: NewBus()})
+	digestAll(t, crashed, payloads[:split])
+	var buf bytes.Buffer
+	if err := crashed.SaveSession(&buf); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Fatal("session contains the auth token")
+	}
+
+	restarted := New(Config{Bus: NewBus()})
+	if err := restarted.LoadSession(&buf); err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if restarted.Snapshot() == nil {
+		t.Fatal("no state after LoadSession")
+	}
+	got := digestAll(t, restarted, payloads[split:])
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("events after restart = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(adr(restarted.Snapshot()), adr(reference.Snapshot())) {
+		t.Fatalf("ADR after restart = %v, want %v", adr(restarted.Snapshot()), adr(reference.Snapshot()))
+	}
+	if restarted.seq.Load() != reference.seq.Load() {
+		t.Fatalf("seq after restart = %d, want %d", restarted.seq.Load(), reference.seq.Load())
+	}
+}
+
+func TestSessionFileCheckpoint(t *testing.T) {
+	payloads := readCapturePayloads(t, "testdata/golden/bomb_round/capture.ndjson")
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	gsi := New(Config{Bus: NewBus(), SessionFile: path})
+	digestAll(t, gsi, payloads)
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("no checkpoint after a round end: %v", err)
+	}
+
+	restarted := New(Config{Bus: NewBus(), SessionFile: path})
+	state := restarted.Snapshot()
+	if state == nil || state.Map.Name != "de_mirage" {
+		t.Fatalf("restored state = %+v", state)
+	}
+}
+
+func TestSessionKeepsMIRVKills(t *testing.T) {
+	payloads := readCapturePayloads(t, "testdata/golden/bomb_round/capture.ndjson")
+	gsi := New(Config{Bus: NewBus()})
+	digestAll(t, gsi, payloads[:3])
+	gsi.mirvKills = true
+
+	var buf bytes.Buffer
+	if err := gsi.SaveSession(&buf); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+	restarted := New(Config{Bus: NewBus()})
+	if err := restarted.LoadSession(&buf); err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if !restarted.mirvKills {
+		t.Fatal("kills inferred again after a restart with a MIRV feed")
+	}
+}
+
+func TestLoadSessionVersion(t *testing.T) {
+	gsi := New(Config{Bus: NewBus()})
+	err := gsi.LoadSession(strings.NewReader(`{"version":99}`))
+	if !errors.Is(err, ErrSessionVersion) {
+		t.Fatalf("LoadSession = %v, want ErrSessionVersion", err)
+	}
+}
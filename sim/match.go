@@ -0,0 +1,850 @@
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nescabir/go-cs2-gsi/capture"
+	rawModels "github.com/nescabir/go-cs2-gsi/raw"
+)
+
+// stage is the part of the match Next generates next
+type stage int
+
+const (
+	stageWarmup stage = iota
+	stageRound
+	stageDone
+)
+
+const (
+	startMoney         = 800
+	overtimeStartMoney = 12500
+	maxMoney           = 16000
+
+	freezetime   = 15 * time.Second
+	roundTime    = 115 * time.Second
+	bombTime     = 40 * time.Second
+	overTime     = 7 * time.Second
+	halftimeTime = 15 * time.Second
+
+	winReward      = 3250
+	bombWinReward  = 3500
+	lossBonus      = 1400
+	lossBonusStep  = 500
+	lossBonusSteps = 4
+	plantBonus     = 800
+	planterReward  = 300
+
+	// spectatorID is the steam id of the observer the game client runs as
+	spectatorID = "76561198000000000"
+)
+
+// plantSites are bomb positions on de_mirage: A, then B
+var plantSites = [2][3]float32{{-300, -2100, -170}, {-2100, 400, -160}}
+
+// play generates the payloads of the next stage
+func (m *Match) play() {
+	switch m.stage {
+	case stageWarmup:
+		m.warmup()
+		m.stage = stageRound
+	case stageRound:
+		m.freezetime()
+		m.live()
+		m.afterRound()
+	}
+}
+
+func (m *Match) warmup() {
+	m.mapPhase = rawModels.MapPhaseWarmup
+	m.roundPhase = rawModels.RoundPhaseLive
+	for _, p := range m.players {
+		p.resetLoadout()
+		p.health = 100
+		p.money = startMoney
+		p.position = m.spawn(p)
+	}
+	m.giveBomb()
+	m.setCountdown(rawModels.PhaseTypeWarmup, 60*time.Second)
+	for i := 0; i < 3; i++ {
+		m.emit()
+		m.advance(20 * time.Second)
+	}
+	m.mapPhase = rawModels.MapPhaseLive
+}
+
+func (m *Match) freezetime() {
+	m.roundPhase = rawModels.RoundPhaseFreezeTime
+	m.winTeam = rawModels.NilSide
+	m.roundBomb = rawModels.BombRoundStateNil
+	m.grenades = nil
+	m.hurtBy = map[*player]*player{}
+	m.forced = m.forcedWinner()
+	m.setCountdown(rawModels.PhaseTypeFreezetime, freezetime)
+
+	for _, p := range m.players {
+		if p.health == 0 {
+			p.resetLoadout()
+		}
+		p.health = 100
+		p.money = min(p.money+p.pending, maxMoney)
+		p.pending = 0
+		p.roundKills, p.roundKillHS, p.roundDamage = 0, 0, 0
+		p.position = m.spawn(p)
+	}
+	m.giveBomb()
+	m.emit()
+
+	m.advance(10 * time.Second)
+	regulation := m.opts.RegulationMaxRounds
+	pistolRound := m.round == 0 || m.round == regulation
+	for _, t := range m.teams {
+		style := teamBuy(t, pistolRound)
+		for _, p := range t.players {
+			m.buy(p, style)
+		}
+	}
+	m.emit()
+	m.advance(freezetime - 10*time.Second)
+}
+
+// forcedWinner returns the side that must win the round for regulation to
+// end tied when Options.Overtime is set
+func (m *Match) forcedWinner() rawModels.Side {
+	regulation := m.opts.RegulationMaxRounds
+	if !m.opts.Overtime || m.round >= 2*regulation {
+		return rawModels.NilSide
+	}
+	for _, t := range m.teams {
+		if t.score == regulation {
+			return other(t.side)
+		}
+	}
+	return rawModels.NilSide
+}
+
+func (m *Match) live() {
+	m.roundPhase = rawModels.RoundPhaseLive
+	m.setCountdown(rawModels.PhaseTypeLive, roundTime)
+	m.roundEnds = m.phaseEnds
+	start := m.now
+	m.emit()
+
+	for m.roundPhase == rawModels.RoundPhaseLive {
+		cts, ts := m.alive(rawModels.CTSide), m.alive(rawModels.TSide)
+		planted := m.bomb.state == rawModels.BombStatePlanted
+		switch {
+		case len(cts) == 0:
+			m.endRound(rawModels.TSide, rawModels.TWinElimination)
+		case planted && !m.now.Before(m.bomb.explodes):
+			m.bomb.state = rawModels.BombStateExploded
+			m.roundBomb = rawModels.BombRoundStateExploded
+			m.endRound(rawModels.TSide, rawModels.TWinBomb)
+		case !planted && len(ts) == 0:
+			m.endRound(rawModels.CTSide, rawModels.CTWinElimination)
+		case !planted && !m.now.Before(m.roundEnds):
+			m.endRound(rawModels.CTSide, rawModels.CTWinTimeLimit)
+		case planted && len(ts) == 0:
+			m.defuse(cts, ts)
+		default:
+			m.act(cts, ts, start)
+		}
+	}
+}
+
+// act plays the next moment of a live round: a kill, damage, a grenade or
+// something happening to the bomb
+func (m *Match) act(cts, ts []*player, start time.Time) {
+	planted := m.bomb.state == rawModels.BombStatePlanted
+	deadline := m.roundEnds
+	if planted {
+		deadline = m.bomb.explodes
+	}
+	wait := time.Duration(3+m.rng.Intn(10)) * time.Second
+	if !m.now.Add(wait).Before(deadline) {
+		m.advance(deadline.Sub(m.now))
+		return
+	}
+	m.advance(wait)
+
+	r := m.rng.Float64()
+	switch {
+	case m.forced != rawModels.NilSide:
+		m.fight(m.forced, true)
+	case m.bomb.state == rawModels.BombStateDropped:
+		m.pickUpBomb(ts)
+	case !planted && m.bomb.player != nil && m.now.Sub(start) > 20*time.Second && r < 0.3:
+		m.plant(cts)
+	case planted && len(cts) >= len(ts) && r < 0.25:
+		m.defuse(cts, ts)
+	case r < 0.45:
+		m.throwGrenade(cts, ts)
+	case r < 0.6:
+		m.fight(m.duelWinner(cts, ts), false)
+	default:
+		m.fight(m.duelWinner(cts, ts), true)
+	}
+}
+
+// duelWinner picks the side winning a fight, weighted by players alive and
+// their equipment
+func (m *Match) duelWinner(cts, ts []*player) rawModels.Side {
+	power := func(players []*player) float64 {
+		total := 0.0
+		for _, p := range players {
+			total += float64(1000 + p.equipValue())
+		}
+		return total
+	}
+	ct, t := power(cts), power(ts)
+	if m.rng.Float64()*(ct+t) < ct {
+		return rawModels.CTSide
+	}
+	return rawModels.TSide
+}
+
+// fight makes a player of side damage or kill a player of the other side
+func (m *Match) fight(side rawModels.Side, lethal bool) {
+	attackers, victims := m.alive(side), m.alive(other(side))
+	if len(attackers) == 0 || len(victims) == 0 {
+		return
+	}
+	attacker := attackers[m.rng.Intn(len(attackers))]
+	victim := victims[m.rng.Intn(len(victims))]
+	attacker.position = m.near(victim.position)
+
+	if lethal || victim.health <= 10 {
+		m.kill(attacker, victim, m.rng.Float64() < 0.45)
+		return
+	}
+
+	damage := 10 + m.rng.Intn(min(80, victim.health-10))
+	victim.health -= damage
+	victim.armor = max(victim.armor-damage/2, 0)
+	attacker.roundDamage += damage
+	m.hurtBy[victim] = attacker
+	m.observed = victim
+	m.emit()
+}
+
+func (m *Match) kill(attacker, victim *player, headshot bool) {
+	attacker.roundDamage += victim.health
+	attacker.roundKills++
+	if headshot {
+		attacker.roundKillHS++
+	}
+	attacker.kills++
+	attacker.score += 2
+	attacker.money = min(attacker.money+weapons[attacker.active].Reward, maxMoney)
+	if assister := m.hurtBy[victim]; assister != nil && assister != attacker && assister.team == attacker.team {
+		assister.assists++
+		assister.score++
+	}
+	delete(m.hurtBy, victim)
+
+	victim.health = 0
+	victim.armor = 0
+	victim.helmet = false
+	victim.kit = false
+	victim.deaths++
+	victim.inventory = nil
+	victim.active = ""
+
+	if m.bomb.player == victim {
+		switch m.bomb.state {
+		case rawModels.BombStateCarried, rawModels.BombStatePlanting:
+			m.bomb.state = rawModels.BombStateDropped
+			m.bomb.position = victim.position
+			m.bomb.player = nil
+		case rawModels.BombStateDefusing:
+			m.bomb.state = rawModels.BombStatePlanted
+			m.bomb.player = nil
+			m.countdownPhase = rawModels.PhaseTypeBomb
+			m.phaseEnds = m.bomb.explodes
+		}
+	}
+
+	m.observed = attacker
+	m.emit()
+}
+
+func (m *Match) pickUpBomb(ts []*player) {
+	p := ts[m.rng.Intn(len(ts))]
+	p.position = m.bomb.position
+	p.inventory = append(p.inventory, "weapon_c4")
+	m.bomb.state = rawModels.BombStateCarried
+	m.bomb.player = p
+	m.observed = p
+	m.emit()
+}
+
+func (m *Match) plant(cts []*player) {
+	planter := m.bomb.player
+	site := plantSites[m.rng.Intn(len(plantSites))]
+	planter.position = site
+	m.bomb.state = rawModels.BombStatePlanting
+	m.bomb.position = site
+	m.observed = planter
+	m.emit()
+
+	m.advance(3 * time.Second)
+	if len(cts) > 0 && m.rng.Float64() < 0.15 {
+		m.kill(cts[m.rng.Intn(len(cts))], planter, false)
+		return
+	}
+
+	planter.drop("weapon_c4")
+	planter.score += 2
+	planter.money = min(planter.money+planterReward, maxMoney)
+	m.bomb.state = rawModels.BombStatePlanted
+	m.bomb.player = nil
+	m.bomb.explodes = m.now.Add(bombTime)
+	m.roundBomb = rawModels.BombRoundStatePlanted
+	m.planter = planter
+	m.setCountdown(rawModels.PhaseTypeBomb, bombTime)
+	m.emit()
+}
+
+// defuse starts a defuse, which a terrorist may interrupt. Without enough
+// time left the CTs give up and wait for the explosion.
+func (m *Match) defuse(cts, ts []*player) {
+	defuser := cts[m.rng.Intn(len(cts))]
+	for _, p := range cts {
+		if p.kit {
+			defuser = p
+			break
+		}
+	}
+	duration := 10 * time.Second
+	if defuser.kit {
+		duration = 5 * time.Second
+	}
+	if m.now.Add(duration).After(m.bomb.explodes) {
+		if len(ts) == 0 {
+			m.advance(m.bomb.explodes.Sub(m.now))
+		}
+		return
+	}
+
+	defuser.position = m.bomb.position
+	m.bomb.state = rawModels.BombStateDefusing
+	m.bomb.player = defuser
+	m.bomb.defused = m.now.Add(duration)
+	m.setCountdown(rawModels.PhaseTypeDefuse, duration)
+	m.observed = defuser
+	m.emit()
+
+	if len(ts) > 0 && m.forced != rawModels.CTSide && m.rng.Float64() < 0.5 {
+		m.advance(duration / 2)
+		m.kill(ts[m.rng.Intn(len(ts))], defuser, m.rng.Float64() < 0.45)
+		return
+	}
+
+	m.advance(duration)
+	defuser.score += 2
+	m.bomb.state = rawModels.BombStateDefused
+	m.roundBomb = rawModels.BombRoundStateDefused
+	m.endRound(rawModels.CTSide, rawModels.CTWinDefuse)
+}
+
+// throwGrenade makes a player switch to a grenade and throw it
+func (m *Match) throwGrenade(cts, ts []*player) {
+	var throwers []*player
+	for _, p := range append(append([]*player(nil), cts...), ts...) {
+		if p.grenade() != "" {
+			throwers = append(throwers, p)
+		}
+	}
+	if len(throwers) == 0 {
+		m.fight(m.duelWinner(cts, ts), false)
+		return
+	}
+
+	p := throwers[m.rng.Intn(len(throwers))]
+	name := p.grenade()
+	p.active = name
+	m.observed = p
+	m.emit()
+
+	m.advance(time.Second)
+	p.drop(name)
+	p.equipBest()
+	info := weapons[name]
+	m.nextGrenade++
+	m.grenades = append(m.grenades, &grenade{
+		id:       strconv.Itoa(m.nextGrenade),
+		owner:    p,
+		kind:     info.Grenade,
+		position: m.near(p.position),
+		thrown:   m.now,
+		expires:  m.now.Add(time.Duration(info.Lifetime * float64(time.Second))),
+	})
+	m.emit()
+}
+
+// endRound ends the live round and pays the teams
+func (m *Match) endRound(winnerSide rawModels.Side, outcome rawModels.RoundOutcome) {
+	winner, loser := m.teamOn(winnerSide), m.teamOn(other(winnerSide))
+
+	m.roundPhase = rawModels.RoundPhaseOver
+	m.winTeam = winnerSide
+	m.round++
+	winner.score++
+	m.roundWins[m.roundWinKey()] = outcome
+	m.setCountdown(rawModels.PhaseTypeOver, overTime)
+	if winner.score >= m.winningScore() {
+		m.mapPhase = rawModels.MapPhaseGameOver
+	}
+
+	reward := winReward
+	if outcome == rawModels.CTWinDefuse || outcome == rawModels.TWinBomb {
+		reward = bombWinReward
+	}
+	for _, p := range winner.players {
+		p.pending += reward
+	}
+	loser.losses++
+	winner.losses = max(winner.losses-1, 0)
+	bonus := lossBonus + lossBonusStep*min(loser.losses-1, lossBonusSteps)
+	for _, p := range loser.players {
+		// Terrorists alive when the time runs out get nothing
+		if outcome == rawModels.CTWinTimeLimit && p.health > 0 {
+			continue
+		}
+		p.pending += bonus
+		if m.planter != nil && loser.side == rawModels.TSide {
+			p.pending += plantBonus
+		}
+	}
+
+	// The defuser or planter of a bomb round, or else the top fragger
+	var mvp *player
+	switch outcome {
+	case rawModels.CTWinDefuse:
+		mvp = m.bomb.player
+	case rawModels.TWinBomb:
+		mvp = m.planter
+	default:
+		for _, p := range winner.players {
+			if p.roundKills > 0 && (mvp == nil || p.roundKills > mvp.roundKills) {
+				mvp = p
+			}
+		}
+	}
+	if mvp != nil {
+		mvp.mvps++
+	}
+
+	m.emit()
+	m.advance(overTime)
+}
+
+// winningScore is the score that wins the match in the current half
+func (m *Match) winningScore() int {
+	return m.opts.RegulationMaxRounds + 1 + m.overtimes*m.opts.OvertimeMaxRounds
+}
+
+// roundWinKey numbers rounds like map.round_wins, which starts again from 1
+// in each overtime
+func (m *Match) roundWinKey() string {
+	round := m.round
+	if m.overtimes > 0 {
+		round -= 2*m.opts.RegulationMaxRounds + 2*m.opts.OvertimeMaxRounds*(m.overtimes-1)
+	}
+	return strconv.Itoa(round)
+}
+
+// afterRound swaps sides at halftime, starts overtimes and ends the match
+func (m *Match) afterRound() {
+	m.bomb = bomb{}
+	m.planter = nil
+	if m.mapPhase == rawModels.MapPhaseGameOver {
+		m.stage = stageDone
+		return
+	}
+
+	regulation, overtime := m.opts.RegulationMaxRounds, m.opts.OvertimeMaxRounds
+	switch {
+	case m.round == regulation:
+		m.halftime(startMoney)
+	case m.round == 2*regulation || m.round > 2*regulation && (m.round-2*regulation)%(2*overtime) == 0:
+		// Tied: play another overtime on the same sides
+		m.overtimes++
+		m.roundWins = map[string]rawModels.RoundOutcome{}
+		m.resetEconomy(overtimeStartMoney)
+	case m.round > 2*regulation && (m.round-2*regulation)%(2*overtime) == overtime:
+		m.halftime(overtimeStartMoney)
+	}
+}
+
+// halftime plays the intermission and swaps sides
+func (m *Match) halftime(money int) {
+	m.mapPhase = rawModels.MapPhaseIntermission
+	m.setCountdown(rawModels.PhaseTypeOver, halftimeTime)
+	m.emit()
+	m.advance(halftimeTime)
+
+	for _, t := range m.teams {
+		t.side = other(t.side)
+	}
+	m.resetEconomy(money)
+	m.mapPhase = rawModels.MapPhaseLive
+}
+
+// resetEconomy starts a half: everyone gets money and a default loadout
+func (m *Match) resetEconomy(money int) {
+	for _, t := range m.teams {
+		t.losses = 0
+	}
+	for _, p := range m.players {
+		p.money = money
+		p.pending = 0
+		p.resetLoadout()
+	}
+}
+
+// giveBomb gives the C4 to a random terrorist
+func (m *Match) giveBomb() {
+	for _, p := range m.players {
+		p.drop("weapon_c4")
+	}
+	ts := m.alive(rawModels.TSide)
+	carrier := ts[m.rng.Intn(len(ts))]
+	carrier.inventory = append(carrier.inventory, "weapon_c4")
+	m.bomb = bomb{state: rawModels.BombStateCarried, player: carrier}
+}
+
+func (m *Match) setCountdown(phase rawModels.PhaseType, d time.Duration) {
+	m.countdownPhase = phase
+	m.phaseEnds = m.now.Add(d)
+}
+
+func (m *Match) advance(d time.Duration) {
+	m.now = m.now.Add(d)
+}
+
+// alive returns the living players of a side
+func (m *Match) alive(side rawModels.Side) []*player {
+	var players []*player
+	for _, p := range m.players {
+		if p.team.side == side && p.health > 0 {
+			players = append(players, p)
+		}
+	}
+	return players
+}
+
+func (m *Match) teamOn(side rawModels.Side) *team {
+	if m.teams[0].side == side {
+		return m.teams[0]
+	}
+	return m.teams[1]
+}
+
+func other(side rawModels.Side) rawModels.Side {
+	if side == rawModels.CTSide {
+		return rawModels.TSide
+	}
+	return rawModels.CTSide
+}
+
+func (m *Match) spawn(p *player) [3]float32 {
+	offset := float32(p.slot%5) * 48
+	if p.team.side == rawModels.CTSide {
+		return [3]float32{-1650 + offset, -1950, -170}
+	}
+	return [3]float32{1200 + offset, 0, -160}
+}
+
+// near returns a position a few meters from pos
+func (m *Match) near(pos [3]float32) [3]float32 {
+	return [3]float32{
+		pos[0] + float32(m.rng.Intn(801)-400),
+		pos[1] + float32(m.rng.Intn(801)-400),
+		pos[2],
+	}
+}
+
+// emit queues the current state as a payload
+func (m *Match) emit() {
+	live := m.grenades[:0]
+	for _, g := range m.grenades {
+		if m.now.Before(g.expires) {
+			live = append(live, g)
+		}
+	}
+	m.grenades = live
+
+	if m.observed == nil || m.observed.health == 0 {
+		m.observed = m.players[0]
+		for _, p := range m.players {
+			if p.health > 0 {
+				m.observed = p
+				break
+			}
+		}
+	}
+
+	state := &rawModels.State{
+		Provider: &rawModels.Provider{
+			Name:      "Counter-Strike: Global Offensive",
+			AppId:     730,
+			Version:   14050,
+			SteamId:   spectatorID,
+			Timestamp: float32(m.now.Unix()),
+		},
+		Map: &rawModels.Map{
+			Mode:       "competitive",
+			Name:       m.opts.Map,
+			Phase:      m.mapPhase,
+			Round:      m.round,
+			Team_ct:    m.teamOn(rawModels.CTSide).raw(),
+			Team_t:     m.teamOn(rawModels.TSide).raw(),
+			Round_wins: make(map[string]rawModels.RoundOutcome, len(m.roundWins)),
+		},
+		Round: &rawModels.Round{
+			Phase:    m.roundPhase,
+			Win_team: m.winTeam,
+			Bomb:     m.roundBomb,
+		},
+		Player:     &rawModels.PlayerObserved{Player: *m.observed.raw()},
+		AllPlayers: make(map[string]*rawModels.Player, len(m.players)),
+		Bomb: &rawModels.Bomb{
+			State:    m.bomb.state,
+			Position: vector(m.bomb.position),
+		},
+		Grenades: make(map[string]*rawModels.Grenade, len(m.grenades)),
+		Phase_countdowns: &rawModels.PhaseCountdown{
+			Phase:         m.countdownPhase,
+			Phase_ends_in: seconds(m.phaseEnds.Sub(m.now)),
+		},
+		Auth: &rawModels.Auth{Token: m.opts.Token},
+	}
+	for round, outcome := range m.roundWins {
+		state.Map.Round_wins[round] = outcome
+	}
+	for _, p := range m.players {
+		state.AllPlayers[p.steamID] = p.raw()
+	}
+
+	switch m.bomb.state {
+	case rawModels.BombStateCarried:
+		state.Bomb.Player = m.bomb.player.steamID
+		state.Bomb.Position = vector(m.bomb.player.position)
+	case rawModels.BombStatePlanting:
+		state.Bomb.Player = m.bomb.player.steamID
+	case rawModels.BombStatePlanted:
+		state.Bomb.Countdown = seconds(m.bomb.explodes.Sub(m.now))
+	case rawModels.BombStateDefusing:
+		state.Bomb.Player = m.bomb.player.steamID
+		state.Bomb.Countdown = seconds(m.bomb.defused.Sub(m.now))
+	}
+
+	for _, g := range m.grenades {
+		raw := &rawModels.Grenade{
+			Owner:    g.owner.steamID,
+			Position: vector(g.position),
+			Velocity: "0.00, 0.00, 0.00",
+			Type:     g.kind,
+			Lifetime: seconds(m.now.Sub(g.thrown)),
+		}
+		switch g.kind {
+		case rawModels.GrenadeTypeSmoke:
+			raw.EffectTime = seconds(m.now.Sub(g.thrown))
+		case rawModels.GrenadeTypeIncendiary:
+			raw.Flames = map[string]string{"flame_0": vector(g.position)}
+		}
+		state.Grenades[g.id] = raw
+	}
+
+	payload, err := json.Marshal(state)
+	if err != nil {
+		panic(fmt.Sprintf("sim: encode payload: %v", err))
+	}
+	m.queue = append(m.queue, capture.Entry{Time: m.now, Source: capture.SourceGSI, Payload: payload})
+}
+
+func (t *team) raw() *rawModels.Team {
+	return &rawModels.Team{
+		Name:                     t.name,
+		Score:                    t.score,
+		Consecutive_round_losses: t.losses,
+		Timeouts_remaining:       t.timeouts,
+	}
+}
+
+func (p *player) raw() *rawModels.Player {
+	weaponsBlock := make(map[string]*rawModels.Weapon, len(p.inventory))
+	for i, name := range p.inventory {
+		info := weapons[name]
+		state := rawModels.WeaponStateHolstered
+		if name == p.active {
+			state = rawModels.WeaponStateActive
+		}
+		weaponsBlock["weapon_"+strconv.Itoa(i)] = &rawModels.Weapon{
+			Name:          name,
+			PaintKit:      "default",
+			Type:          info.Type,
+			State:         state,
+			Ammo_clip:     info.Clip,
+			Ammo_clip_max: info.Clip,
+			Ammo_reserve:  info.Reserve,
+		}
+	}
+	return &rawModels.Player{
+		Steamid:       p.steamID,
+		Name:          p.name,
+		Observer_slot: p.slot,
+		Team:          p.team.side,
+		Activity:      rawModels.PlayerActivityActive,
+		State: &rawModels.PlayerState{
+			Health:         p.health,
+			Armor:          p.armor,
+			Helmet:         p.helmet,
+			DefuseKit:      p.kit,
+			Money:          p.money,
+			Round_kills:    p.roundKills,
+			Round_killhs:   p.roundKillHS,
+			Round_totaldmg: p.roundDamage,
+			Equip_value:    p.equipValue(),
+		},
+		Weapons: weaponsBlock,
+		Match_stats: &rawModels.PlayerMatchStats{
+			Kills:   p.kills,
+			Assists: p.assists,
+			Deaths:  p.deaths,
+			Mvps:    p.mvps,
+			Score:   p.score,
+		},
+		Position: vector(p.position),
+		Forward:  "0.00, 1.00, 0.00",
+	}
+}
+
+// resetLoadout leaves the player with the default knife and pistol
+func (p *player) resetLoadout() {
+	loadout := loadouts[p.team.side]
+	p.inventory = []string{loadout.knife, loadout.pistol}
+	p.active = loadout.pistol
+	p.armor = 0
+	p.helmet = false
+	p.kit = false
+}
+
+// purchase buys a weapon if the player can afford it and has room for it
+func (p *player) purchase(name string) {
+	info := weapons[name]
+	if p.money < info.Price || p.has(name) || isPrimary(name) && p.hasPrimary() {
+		return
+	}
+	if info.Type == rawModels.WeaponTypePistol {
+		for _, owned := range p.inventory {
+			if weapons[owned].Type == rawModels.WeaponTypePistol {
+				p.drop(owned)
+			}
+		}
+	}
+	p.money -= info.Price
+	p.inventory = append(p.inventory, name)
+}
+
+// buyArmor buys kevlar, or kevlar and helmet, when missing
+func (p *player) buyArmor(helmet bool) {
+	switch {
+	case p.armor < 100 && helmet && p.money >= kevlarPrice+helmetPrice:
+		p.money -= kevlarPrice + helmetPrice
+		p.armor, p.helmet = 100, true
+	case p.armor < 100 && p.money >= kevlarPrice:
+		p.money -= kevlarPrice
+		p.armor = 100
+	case p.armor == 100 && helmet && !p.helmet && p.money >= helmetPrice:
+		p.money -= helmetPrice
+		p.helmet = true
+	}
+}
+
+func (p *player) has(name string) bool {
+	for _, owned := range p.inventory {
+		if owned == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *player) hasPrimary() bool {
+	return p.primary() != ""
+}
+
+func (p *player) primary() string {
+	for _, name := range p.inventory {
+		if isPrimary(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// grenade returns the first grenade in the inventory
+func (p *player) grenade() string {
+	for _, name := range p.inventory {
+		if weapons[name].Type == rawModels.WeaponTypeGrenade {
+			return name
+		}
+	}
+	return ""
+}
+
+func (p *player) drop(name string) {
+	for i, owned := range p.inventory {
+		if owned == name {
+			p.inventory = append(p.inventory[:i:i], p.inventory[i+1:]...)
+			return
+		}
+	}
+}
+
+// equipBest switches to the primary, or else the pistol
+func (p *player) equipBest() {
+	p.active = p.primary()
+	if p.active != "" {
+		return
+	}
+	for _, name := range p.inventory {
+		switch weapons[name].Type {
+		case rawModels.WeaponTypePistol:
+			p.active = name
+			return
+		case rawModels.WeaponTypeKnife:
+			p.active = name
+		}
+	}
+}
+
+func (p *player) equipValue() int {
+	value := 0
+	for _, name := range p.inventory {
+		value += weapons[name].Price
+	}
+	if p.armor > 0 {
+		value += kevlarPrice
+	}
+	if p.helmet {
+		value += helmetPrice
+	}
+	if p.kit {
+		value += kitPrice
+	}
+	return value
+}
+
+func vector(v [3]float32) string {
+	return fmt.Sprintf("%.2f, %.2f, %.2f", v[0], v[1], v[2])
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(max(d.Seconds(), 0), 'f', 1, 64)
+}
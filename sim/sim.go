@@ -0,0 +1,277 @@
+// Package sim plays synthetic matches and produces the GSI payloads CS2
+// would send for them, in order, so overlays can be developed and tested
+// without running the game.
+//
+// A match covers warmup, freezetime and buys, live play with kills, damage
+// and grenades, bomb plants and defuses, round wins, halftime side swaps,
+// overtime and gameover, with ten players. It is deterministic: the same
+// Options always produce the same payloads.
+package sim
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	"github.com/nescabir/go-cs2-gsi/capture"
+	rawModels "github.com/nescabir/go-cs2-gsi/raw"
+)
+
+// Options configures a simulated match
+type Options struct {
+	// Seed selects the match; the same seed and options always produce the
+	// same payloads
+	Seed int64
+	// Map is the map played. Defaults to de_mirage.
+	Map string
+	// Teams names the team starting on CT and the team starting on T.
+	// Defaults to Blue and Red.
+	Teams [2]string
+	// RegulationMaxRounds is the number of rounds in a regulation half.
+	// Defaults to 12, like Config.RegulationMaxRounds.
+	RegulationMaxRounds int
+	// OvertimeMaxRounds is the number of rounds in an overtime half.
+	// Defaults to 3, like Config.OvertimeMaxRounds.
+	OvertimeMaxRounds int
+	// Overtime makes regulation end tied, so the match always goes to
+	// overtime
+	Overtime bool
+	// Start is the game clock of the first payload. Defaults to
+	// 2025-01-01 UTC, so payloads do not depend on when they are generated.
+	Start time.Time
+	// Token is sent as the auth token
+	Token string
+	// Speed scales the pace of Run: 1 sends payloads in real time, 2 twice
+	// as fast. Zero or less sends them as fast as possible.
+	Speed float64
+}
+
+func (o *Options) setDefaults() {
+	if o.Map == "" {
+		o.Map = "de_mirage"
+	}
+	if o.Teams[0] == "" {
+		o.Teams[0] = "Blue"
+	}
+	if o.Teams[1] == "" {
+		o.Teams[1] = "Red"
+	}
+	if o.RegulationMaxRounds <= 0 {
+		o.RegulationMaxRounds = 12
+	}
+	if o.OvertimeMaxRounds <= 0 {
+		o.OvertimeMaxRounds = 3
+	}
+	if o.Start.IsZero() {
+		o.Start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Sink receives the payloads of a match
+type Sink func(ctx context.Context, payload []byte) error
+
+// Digest returns a sink passing payloads to gsi.Digest
+func Digest(gsi *cs2gsi.CS2GSI) Sink {
+	return func(ctx context.Context, payload []byte) error {
+		return gsi.Digest(payload)
+	}
+}
+
+// Post returns a sink posting payloads to a game state endpoint, such as
+// the one served by Listen. A nil client uses http.DefaultClient.
+func Post(url string, client *http.Client) Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context, payload []byte) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("post payload: %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+// Match is a simulated match. Next generates its payloads one at a time;
+// Run sends all of them to a sink.
+type Match struct {
+	opts Options
+	rng  *rand.Rand
+	now  time.Time
+
+	teams   [2]*team
+	players []*player
+	stage   stage
+	queue   []capture.Entry
+	sent    time.Time
+
+	mapPhase       rawModels.MapPhase
+	round          int
+	overtimes      int
+	roundWins      map[string]rawModels.RoundOutcome
+	roundPhase     rawModels.RoundPhase
+	winTeam        rawModels.Side
+	roundBomb      rawModels.BombRoundState
+	countdownPhase rawModels.PhaseType
+	phaseEnds      time.Time
+	roundEnds      time.Time
+	forced         rawModels.Side
+	planter        *player
+
+	bomb        bomb
+	grenades    []*grenade
+	nextGrenade int
+	observed    *player
+	hurtBy      map[*player]*player
+}
+
+// team is one of the two teams, whatever side it currently plays
+type team struct {
+	name     string
+	side     rawModels.Side
+	score    int
+	losses   int
+	timeouts int
+	players  []*player
+}
+
+// player is one of the ten players
+type player struct {
+	steamID string
+	name    string
+	team    *team
+	slot    int
+
+	health    int
+	armor     int
+	helmet    bool
+	kit       bool
+	money     int
+	pending   int
+	inventory []string
+	active    string
+	position  [3]float32
+
+	roundKills  int
+	roundKillHS int
+	roundDamage int
+	kills       int
+	assists     int
+	deaths      int
+	mvps        int
+	score       int
+}
+
+// bomb is the C4
+type bomb struct {
+	state    rawModels.BombState
+	player   *player
+	position [3]float32
+	explodes time.Time
+	defused  time.Time
+}
+
+// grenade is a thrown grenade still in the grenades block
+type grenade struct {
+	id       string
+	owner    *player
+	kind     rawModels.GrenadeType
+	position [3]float32
+	thrown   time.Time
+	expires  time.Time
+}
+
+var playerNames = [10]string{
+	"Ace", "Blaze", "Cipher", "Dash", "Echo",
+	"Frost", "Ghost", "Havoc", "Ion", "Jinx",
+}
+
+// NewMatch sets up a match; nothing is generated until Next or Run
+func NewMatch(opts Options) *Match {
+	opts.setDefaults()
+	m := &Match{
+		opts:      opts,
+		rng:       rand.New(rand.NewSource(opts.Seed)),
+		now:       opts.Start,
+		roundWins: map[string]rawModels.RoundOutcome{},
+		hurtBy:    map[*player]*player{},
+	}
+	sides := [2]rawModels.Side{rawModels.CTSide, rawModels.TSide}
+	for i := range m.teams {
+		m.teams[i] = &team{name: opts.Teams[i], side: sides[i], timeouts: 1}
+	}
+	for i, name := range playerNames {
+		t := m.teams[i/5]
+		p := &player{
+			steamID: fmt.Sprintf("765611980000000%02d", i+1),
+			name:    name,
+			team:    t,
+			// Observer slots 1-5 and 6-9, 0 as on the scoreboard
+			slot: (i + 1) % 10,
+		}
+		t.players = append(t.players, p)
+		m.players = append(m.players, p)
+	}
+	return m
+}
+
+// Next returns the next payload of the match, or io.EOF once gameover has
+// been sent. Entry.Time is the game clock of the payload.
+func (m *Match) Next() (capture.Entry, error) {
+	for len(m.queue) == 0 {
+		if m.stage == stageDone {
+			return capture.Entry{}, io.EOF
+		}
+		m.play()
+	}
+	entry := m.queue[0]
+	m.queue = m.queue[1:]
+	return entry, nil
+}
+
+// Run sends the remaining payloads to sink, paced by Options.Speed, until
+// the match is over, ctx is done or the sink fails
+func (m *Match) Run(ctx context.Context, sink Sink) error {
+	for {
+		entry, err := m.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if m.opts.Speed > 0 && !m.sent.IsZero() {
+			delay := time.Duration(float64(entry.Time.Sub(m.sent)) / m.opts.Speed)
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := sink(ctx, entry.Payload); err != nil {
+			return fmt.Errorf("payload at %s: %w", entry.Time.Format(time.TimeOnly), err)
+		}
+		m.sent = entry.Time
+	}
+}
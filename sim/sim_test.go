@@ -0,0 +1,170 @@
+package sim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	cs2gsi "github.com/nescabir/go-cs2-gsi"
+	"github.com/nescabir/go-cs2-gsi/gsitest"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+func payloads(t *testing.T, opts Options) [][]byte {
+	t.Helper()
+	match := NewMatch(opts)
+	var out [][]byte
+	for {
+		entry, err := match.Next()
+		if errors.Is(err, io.EOF) {
+			return out
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		out = append(out, entry.Payload)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	a := payloads(t, Options{Seed: 7})
+	b := payloads(t, Options{Seed: 7})
+	if len(a) != len(b) {
+		t.Fatalf("payload counts differ: %d vs %d", len(a), len(b))
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			t.Fatalf("payload %d differs between runs with the same seed", i)
+		}
+	}
+
+	c := payloads(t, Options{Seed: 8})
+	if len(a) == len(c) && bytes.Equal(a[len(a)-1], c[len(c)-1]) {
+		t.Fatal("different seeds produced the same match")
+	}
+}
+
+func TestFullMatch(t *testing.T) {
+	gsi := cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus()})
+	rec := gsitest.NewRecorder(t, gsi)
+
+	if err := NewMatch(Options{Seed: 1}).Run(context.Background(), Digest(gsi)); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	end := gsitest.ExpectEvent(t, rec, cs2gsi.MatchEnd)
+	if end.Data.Winner.Score != 13 || end.Data.Loser.Score > 11 {
+		t.Fatalf("final score = %d-%d, want 13 to at most 11", end.Data.Winner.Score, end.Data.Loser.Score)
+	}
+	rounds := gsitest.EventsOf(rec, cs2gsi.RoundEnd)
+	if played := end.Data.Winner.Score + end.Data.Loser.Score; len(rounds) != played {
+		t.Fatalf("%d round ends for %d rounds played", len(rounds), played)
+	}
+	if n := len(gsitest.EventsOf(rec, cs2gsi.FreezetimeEnd)); n != len(rounds) {
+		t.Fatalf("%d freezetime ends for %d rounds", n, len(rounds))
+	}
+	buys := gsitest.EventsOf(rec, cs2gsi.BuySummary)
+	if len(buys) != 2*len(rounds) {
+		t.Fatalf("%d buy summaries for %d rounds", len(buys), len(rounds))
+	}
+	if buys[0].Data.Type != models.BuyTypePistol {
+		t.Fatalf("first round buy = %s, want pistol", buys[0].Data.Type)
+	}
+	gsitest.ExpectEvent(t, rec, cs2gsi.IntermissionStart)
+	for _, name := range []cs2gsi.EventName[*models.Player]{cs2gsi.BombPlanted, cs2gsi.Mvp} {
+		if len(gsitest.EventsOf(rec, name)) == 0 {
+			t.Errorf("no %s event in a full match", name)
+		}
+	}
+	for _, name := range []cs2gsi.EventName[*models.WeaponEvent]{cs2gsi.WeaponPurchase, cs2gsi.GrenadeThrow} {
+		if len(gsitest.EventsOf(rec, name)) == 0 {
+			t.Errorf("no %s event in a full match", name)
+		}
+	}
+
+	state := gsi.Snapshot()
+	if len(state.AllPlayers) != 10 {
+		t.Fatalf("players = %d, want 10", len(state.AllPlayers))
+	}
+	if state.Map.Phase != models.MapPhaseGameOver {
+		t.Fatalf("map phase = %s, want gameover", state.Map.Phase)
+	}
+}
+
+func TestOvertime(t *testing.T) {
+	gsi := cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus(), RegulationMaxRounds: 3, OvertimeMaxRounds: 2})
+	rec := gsitest.NewRecorder(t, gsi)
+
+	opts := Options{Seed: 3, Overtime: true, RegulationMaxRounds: 3, OvertimeMaxRounds: 2}
+	if err := NewMatch(opts).Run(context.Background(), Digest(gsi)); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	end := gsitest.ExpectEvent(t, rec, cs2gsi.MatchEnd)
+	winner, loser := end.Data.Winner.Score, end.Data.Loser.Score
+	// Regulation ends 3-3; each overtime is won at 4+2k
+	if winner <= 4 || (winner-4)%2 != 0 || loser < 3 || loser >= winner {
+		t.Fatalf("final score = %d-%d, want an overtime win", winner, loser)
+	}
+	// Regulation halftime plus one per overtime
+	if n := len(gsitest.EventsOf(rec, cs2gsi.IntermissionStart)); n < 2 {
+		t.Fatalf("%d intermissions, want at least 2", n)
+	}
+}
+
+func TestEconomyForecast(t *testing.T) {
+	gsi := cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus(), RegulationMaxRounds: 3, OvertimeMaxRounds: 2})
+
+	// The money expected at each round end is what players start the next
+	// round with, halftime and overtime resets included
+	var expected map[string]int
+	cs2gsi.SubscribeTo(gsi, cs2gsi.RoundEnd, func(event cs2gsi.Event[*models.Score]) {
+		expected = make(map[string]int)
+		for _, forecast := range []*models.EconomyForecast{event.Data.Economy.CT, event.Data.Economy.T} {
+			for _, player := range forecast.Players {
+				expected[player.SteamId] = player.Expected
+			}
+		}
+	})
+	checked := 0
+	cs2gsi.SubscribeTo(gsi, cs2gsi.FreezetimeStart, func(event cs2gsi.Event[*models.Player]) {
+		if expected == nil {
+			return
+		}
+		for id, player := range gsi.Snapshot().AllPlayers {
+			if player.State.Money != expected[id] {
+				t.Errorf("round %d: %s starts with $%d, expected $%d", event.Round, id, player.State.Money, expected[id])
+			}
+		}
+		checked++
+	})
+
+	opts := Options{Seed: 2, Overtime: true, RegulationMaxRounds: 3, OvertimeMaxRounds: 2}
+	if err := NewMatch(opts).Run(context.Background(), Digest(gsi)); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if checked == 0 {
+		t.Fatal("no round was checked")
+	}
+}
+
+func TestPostSink(t *testing.T) {
+	gsi := cs2gsi.New(cs2gsi.Config{Bus: cs2gsi.NewBus(), ExpectedToken: "secret"})
+	srv := httptest.NewServer(gsi.Handler())
+	defer srv.Close()
+	rec := gsitest.NewRecorder(t, gsi)
+
+	opts := Options{Seed: 2, RegulationMaxRounds: 2, Token: "secret"}
+	if err := NewMatch(opts).Run(context.Background(), Post(srv.URL, srv.Client())); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	gsitest.ExpectEvent(t, rec, cs2gsi.MatchEnd)
+
+	opts.Token = "wrong"
+	if err := NewMatch(opts).Run(context.Background(), Post(srv.URL, srv.Client())); err == nil {
+		t.Fatal("expected the sink to fail on a rejected token")
+	}
+}
@@ -0,0 +1,160 @@
+package sim
+
+import (
+	rawModels "github.com/nescabir/go-cs2-gsi/raw"
+)
+
+// weaponInfo describes a weapon as far as the simulator needs it
+type weaponInfo struct {
+	Type    rawModels.WeaponType
+	Price   int
+	Clip    int
+	Reserve int
+	// Reward is the kill reward
+	Reward int
+	// Grenade is the projectile type of a grenade
+	Grenade rawModels.GrenadeType
+	// Lifetime is how long a thrown grenade stays in the grenades block
+	Lifetime float64
+}
+
+var weapons = map[string]weaponInfo{
+	"weapon_knife":         {Type: rawModels.WeaponTypeKnife, Reward: 1500},
+	"weapon_knife_t":       {Type: rawModels.WeaponTypeKnife, Reward: 1500},
+	"weapon_glock":         {Type: rawModels.WeaponTypePistol, Price: 200, Clip: 20, Reserve: 120, Reward: 300},
+	"weapon_usp_silencer":  {Type: rawModels.WeaponTypePistol, Price: 200, Clip: 12, Reserve: 24, Reward: 300},
+	"weapon_p250":          {Type: rawModels.WeaponTypePistol, Price: 300, Clip: 13, Reserve: 26, Reward: 300},
+	"weapon_deagle":        {Type: rawModels.WeaponTypePistol, Price: 700, Clip: 7, Reserve: 35, Reward: 300},
+	"weapon_mac10":         {Type: rawModels.WeaponTypeSubmachineGun, Price: 1050, Clip: 30, Reserve: 100, Reward: 600},
+	"weapon_mp9":           {Type: rawModels.WeaponTypeSubmachineGun, Price: 1250, Clip: 30, Reserve: 120, Reward: 600},
+	"weapon_nova":          {Type: rawModels.WeaponTypeShotgun, Price: 1050, Clip: 8, Reserve: 32, Reward: 900},
+	"weapon_galilar":       {Type: rawModels.WeaponTypeRifle, Price: 1800, Clip: 35, Reserve: 90, Reward: 300},
+	"weapon_famas":         {Type: rawModels.WeaponTypeRifle, Price: 2050, Clip: 25, Reserve: 90, Reward: 300},
+	"weapon_ak47":          {Type: rawModels.WeaponTypeRifle, Price: 2700, Clip: 30, Reserve: 90, Reward: 300},
+	"weapon_m4a1_silencer": {Type: rawModels.WeaponTypeRifle, Price: 2900, Clip: 20, Reserve: 80, Reward: 300},
+	"weapon_awp":           {Type: rawModels.WeaponTypeSniperRifle, Price: 4750, Clip: 5, Reserve: 30, Reward: 100},
+	"weapon_hegrenade":     {Type: rawModels.WeaponTypeGrenade, Price: 300, Reserve: 1, Reward: 300, Grenade: rawModels.GrenadeTypeFrag, Lifetime: 2},
+	"weapon_flashbang":     {Type: rawModels.WeaponTypeGrenade, Price: 200, Reserve: 1, Reward: 300, Grenade: rawModels.GrenadeTypeFlash, Lifetime: 2},
+	"weapon_smokegrenade":  {Type: rawModels.WeaponTypeGrenade, Price: 300, Reserve: 1, Reward: 300, Grenade: rawModels.GrenadeTypeSmoke, Lifetime: 20},
+	"weapon_molotov":       {Type: rawModels.WeaponTypeGrenade, Price: 400, Reserve: 1, Reward: 300, Grenade: rawModels.GrenadeTypeIncendiary, Lifetime: 8},
+	"weapon_incgrenade":    {Type: rawModels.WeaponTypeGrenade, Price: 500, Reserve: 1, Reward: 300, Grenade: rawModels.GrenadeTypeIncendiary, Lifetime: 8},
+	"weapon_c4":            {Type: rawModels.WeaponTypeC4},
+}
+
+const (
+	kevlarPrice = 650
+	helmetPrice = 350
+	kitPrice    = 400
+)
+
+// sideWeapons are the side-specific weapons of a buy
+type sideWeapons struct {
+	knife, pistol, smg, cheapRifle, rifle, fireGrenade string
+}
+
+var loadouts = map[rawModels.Side]sideWeapons{
+	rawModels.CTSide: {
+		knife:       "weapon_knife",
+		pistol:      "weapon_usp_silencer",
+		smg:         "weapon_mp9",
+		cheapRifle:  "weapon_famas",
+		rifle:       "weapon_m4a1_silencer",
+		fireGrenade: "weapon_incgrenade",
+	},
+	rawModels.TSide: {
+		knife:       "weapon_knife_t",
+		pistol:      "weapon_glock",
+		smg:         "weapon_mac10",
+		cheapRifle:  "weapon_galilar",
+		rifle:       "weapon_ak47",
+		fireGrenade: "weapon_molotov",
+	},
+}
+
+// isPrimary reports whether a weapon goes in the primary slot
+func isPrimary(name string) bool {
+	switch weapons[name].Type {
+	case rawModels.WeaponTypeRifle, rawModels.WeaponTypeSniperRifle, rawModels.WeaponTypeSubmachineGun,
+		rawModels.WeaponTypeShotgun, rawModels.WeaponTypeMachineGun:
+		return true
+	}
+	return false
+}
+
+// buyStyle is how much a team spends in a round
+type buyStyle int
+
+const (
+	buyPistol buyStyle = iota
+	buyEco
+	buyForce
+	buyFull
+)
+
+// teamBuy decides how a team spends from its average money
+func teamBuy(t *team, pistolRound bool) buyStyle {
+	if pistolRound {
+		return buyPistol
+	}
+	total := 0
+	for _, p := range t.players {
+		total += p.money
+	}
+	average := total / len(t.players)
+	switch {
+	case average >= 4000:
+		return buyFull
+	case average >= 2200 || (average >= 1600 && t.losses == 0):
+		return buyForce
+	}
+	return buyEco
+}
+
+// buy spends a player's money according to the team's buy. Weapons kept
+// from the previous round are not bought again.
+func (m *Match) buy(p *player, style buyStyle) {
+	loadout := loadouts[p.team.side]
+
+	switch style {
+	case buyPistol:
+		if m.rng.Float64() < 0.5 {
+			p.buyArmor(false)
+		} else {
+			p.purchase("weapon_p250")
+			p.purchase("weapon_flashbang")
+		}
+	case buyEco:
+		if m.rng.Float64() < 0.3 {
+			p.purchase("weapon_flashbang")
+		}
+	case buyForce:
+		p.buyArmor(false)
+		if !p.hasPrimary() {
+			if m.rng.Float64() < 0.5 && p.money >= weapons[loadout.cheapRifle].Price {
+				p.purchase(loadout.cheapRifle)
+			} else {
+				p.purchase(loadout.smg)
+			}
+		}
+		p.purchase("weapon_flashbang")
+	case buyFull:
+		p.buyArmor(true)
+		if !p.hasPrimary() {
+			if p.slot%5 == 0 && p.money >= weapons["weapon_awp"].Price+1000 {
+				p.purchase("weapon_awp")
+			} else {
+				p.purchase(loadout.rifle)
+			}
+		}
+		if p.team.side == rawModels.CTSide && !p.kit && p.money >= kitPrice {
+			p.money -= kitPrice
+			p.kit = true
+		}
+		for _, grenade := range []string{"weapon_smokegrenade", "weapon_flashbang", loadout.fireGrenade, "weapon_hegrenade"} {
+			if m.rng.Float64() < 0.7 {
+				p.purchase(grenade)
+			}
+		}
+	}
+	p.equipBest()
+}
@@ -0,0 +1,119 @@
+package cs2gsi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// sseKeepAliveInterval is how often a comment line is sent to keep idle
+// connections and proxies from timing out
+const sseKeepAliveInterval = 15 * time.Second
+
+// EventStreamHandler returns a Server-Sent Events endpoint streaming every
+// published event (except Raw and RawMIRV) with its name as the SSE event
+// type and the JSON envelope as data. Clients reconnecting with
+// Last-Event-ID receive the events they missed from a small in-memory
+// buffer; new clients, or clients whose gap is no longer buffered, first
+// receive the current state as a data event. With ?format=csgogsi the event
+// names and payloads follow csgogsi instead. Listen mounts it on GET /events.
+func (gsi *CS2GSI) EventStreamHandler() http.Handler {
+	return http.HandlerFunc(gsi.handleEventStream)
+}
+
+// handleEventStream streams events until the client disconnects or falls
+// too far behind
+func (gsi *CS2GSI) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && err != http.ErrNotSupported {
+		gsi.logger.Warn("failed to clear write deadline", "error", err)
+	}
+
+	format, err := parseStreamFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lastID := lastEventID(r)
+
+	client := newStreamClient(format)
+	backlog, complete := gsi.hub.subscribeAfter(client, lastID)
+	defer gsi.hub.unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if lastID == 0 || !complete {
+		if snapshot := gsi.hub.snapshotMessage(); snapshot != nil {
+			if err := gsi.writeSSE(w, snapshot, format); err != nil {
+				return
+			}
+		}
+	}
+	for _, msg := range backlog {
+		if err := gsi.writeSSE(w, msg, format); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case msg := <-client.send:
+			if err := gsi.writeSSE(w, msg, format); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-client.closed:
+			return
+		case <-r.Context().Done():
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// lastEventID reads the resume position from the Last-Event-ID header, or
+// the lastEventId query parameter for clients that cannot set headers
+func lastEventID(r *http.Request) uint64 {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// writeSSE writes a single event. The snapshot carries no id so it does not
+// move the client's resume position. Events that fail to encode are skipped.
+func (gsi *CS2GSI) writeSSE(w http.ResponseWriter, msg *streamMessage, format streamFormat) error {
+	name, data, err := msg.encode(format)
+	if err != nil {
+		gsi.logger.Error("failed to encode stream event", "event", msg.name, "error", err)
+		return nil
+	}
+
+	if msg.seq != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", msg.seq); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+	return err
+}
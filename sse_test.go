@@ -0,0 +1,192 @@
+package cs2gsi
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// readSSEEvent reads lines until a complete event, skipping comments
+func readSSEEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event.event != "" {
+				return event
+			}
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func openEventStream(t *testing.T, ctx context.Context, url, lastID string) *bufio.Reader {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get events: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q", ct)
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+func waitForStreamClients(t *testing.T, gsi *CS2GSI, want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		gsi.hub.mu.Lock()
+		n := len(gsi.hub.clients)
+		gsi.hub.mu.Unlock()
+		if n == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("stream clients did not reach %d", want)
+}
+
+func TestEventStreamResume(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{Bus: NewBus()})
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	srv := httptest.NewServer(gsi.EventStreamHandler())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := openEventStream(t, ctx, srv.URL, "")
+
+	snapshot := readSSEEvent(t, reader)
+	if snapshot.event != "data" || snapshot.id != "" {
+		t.Fatalf("snapshot = %+v, want data event without id", snapshot)
+	}
+
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	live := readSSEEvent(t, reader)
+	if live.event != "data" || live.id == "" || !strings.Contains(live.data, `"map":"de_dust2"`) {
+		t.Fatalf("live event = %+v", live)
+	}
+
+	cancel()
+	waitForStreamClients(t, gsi, 0)
+
+	for i := 0; i < 2; i++ {
+		if err := gsi.Digest(raw); err != nil {
+			t.Fatalf("Digest: %v", err)
+		}
+	}
+
+	resumeCtx, resumeCancel := context.WithCancel(context.Background())
+	defer resumeCancel()
+	reader = openEventStream(t, resumeCtx, srv.URL, live.id)
+	lastID, _ := strconv.ParseUint(live.id, 10, 64)
+	for i := 0; i < 2; i++ {
+		missed := readSSEEvent(t, reader)
+		id, err := strconv.ParseUint(missed.id, 10, 64)
+		if err != nil || id <= lastID {
+			t.Fatalf("missed event %d = %+v, want id above %d", i, missed, lastID)
+		}
+		lastID = id
+	}
+}
+
+func TestMessageRingEviction(t *testing.T) {
+	ring := newMessageRing(3)
+	for seq := uint64(1); seq <= 5; seq++ {
+		ring.push(&streamMessage{seq: seq})
+	}
+
+	msgs, complete := ring.after(3)
+	if !complete || len(msgs) != 2 || msgs[0].seq != 4 {
+		t.Fatalf("after(3) = %d msgs, complete %v", len(msgs), complete)
+	}
+
+	msgs, complete = ring.after(1)
+	if complete || len(msgs) != 3 {
+		t.Fatalf("after(1) = %d msgs, complete %v; want 3 msgs, incomplete", len(msgs), complete)
+	}
+}
+
+func TestEventStreamCSGOGSIFormat(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{Bus: NewBus()})
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	srv := httptest.NewServer(gsi.EventStreamHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?format=unknown")
+	if err != nil {
+		t.Fatalf("get events: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unknown format status = %d, want 400", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader := openEventStream(t, ctx, srv.URL+"?format=csgogsi", "")
+
+	snapshot := readSSEEvent(t, reader)
+	if !strings.Contains(snapshot.data, `"players":[{"steamid":"76561198000000001"`) {
+		t.Fatalf("snapshot is not csgogsi shaped: %s", snapshot.data)
+	}
+
+	gsi.publishBombExploded(nil)
+	explode := readSSEEvent(t, reader)
+	if explode.event != "bombExplode" || !strings.Contains(explode.data, `"name":"bombExplode"`) {
+		t.Fatalf("event = %+v, want bombExplode", explode)
+	}
+}
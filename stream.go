@@ -0,0 +1,252 @@
+package cs2gsi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/nescabir/go-cs2-gsi/csgogsi"
+	models "github.com/nescabir/go-cs2-gsi/models"
+)
+
+const (
+	// streamClientBuffer is the number of messages queued for a streaming
+	// client before it is considered too slow and disconnected
+	streamClientBuffer = 256
+	// streamHistorySize is the number of recent messages kept for clients
+	// resuming with Last-Event-ID
+	streamHistorySize = 256
+)
+
+// streamFormat selects the JSON shape sent to a streaming client
+type streamFormat int
+
+const (
+	// formatNative sends the Event envelope with models payloads
+	formatNative streamFormat = iota
+	// formatCSGOGSI sends the Event envelope with csgogsi event names and
+	// payloads
+	formatCSGOGSI
+)
+
+// parseStreamFormat reads the format query parameter of a stream request
+func parseStreamFormat(r *http.Request) (streamFormat, error) {
+	switch value := r.URL.Query().Get("format"); value {
+	case "", "native":
+		return formatNative, nil
+	case "csgogsi":
+		return formatCSGOGSI, nil
+	default:
+		return formatNative, fmt.Errorf("unknown stream format %q", value)
+	}
+}
+
+// streamMessage is a published event shared by every streaming client.
+// Each format is serialized once, the first time a client needs it.
+type streamMessage struct {
+	event GameEvent
+	name  string
+	seq   uint64
+
+	native  encodedMessage
+	csgogsi encodedMessage
+}
+
+type encodedMessage struct {
+	once sync.Once
+	name string
+	data []byte
+	err  error
+}
+
+// encode returns the event name and JSON body of the message in a format
+func (m *streamMessage) encode(format streamFormat) (string, []byte, error) {
+	if format == formatCSGOGSI {
+		m.csgogsi.once.Do(func() {
+			envelope := m.event.envelope()
+			envelope.Name = csgogsi.EventName(models.Events(envelope.Name))
+			envelope.Data = csgogsi.Convert(envelope.Data)
+			m.csgogsi.name = envelope.Name
+			m.csgogsi.data, m.csgogsi.err = json.Marshal(envelope)
+		})
+		return m.csgogsi.name, m.csgogsi.data, m.csgogsi.err
+	}
+
+	m.native.once.Do(func() {
+		m.native.name = m.name
+		m.native.data, m.native.err = json.Marshal(m.event)
+	})
+	return m.native.name, m.native.data, m.native.err
+}
+
+// streamClient is a connected WebSocket or SSE consumer
+type streamClient struct {
+	format streamFormat
+	send   chan *streamMessage
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newStreamClient(format streamFormat) *streamClient {
+	return &streamClient{
+		format: format,
+		send:   make(chan *streamMessage, streamClientBuffer),
+		closed: make(chan struct{}),
+	}
+}
+
+// close marks the client as gone; its send queue is no longer written to
+func (c *streamClient) close() {
+	c.once.Do(func() { close(c.closed) })
+}
+
+// hub fans the events of one instance out to streaming clients
+type hub struct {
+	gsi     *CS2GSI
+	start   sync.Once
+	mu      sync.Mutex
+	clients map[*streamClient]struct{}
+	history *messageRing
+}
+
+func newHub(gsi *CS2GSI) *hub {
+	return &hub{
+		gsi:     gsi,
+		clients: make(map[*streamClient]struct{}),
+		history: newMessageRing(streamHistorySize),
+	}
+}
+
+// subscribe registers a client, subscribing the hub to the bus on first use
+func (h *hub) subscribe(c *streamClient) {
+	h.subscribeAfter(c, 0)
+}
+
+// subscribeAfter registers a client and returns the buffered messages
+// published after seq. complete is false when messages after seq have
+// already been evicted from the history. Registration and the history read
+// happen atomically, so no message is missed or repeated.
+func (h *hub) subscribeAfter(c *streamClient, seq uint64) (backlog []*streamMessage, complete bool) {
+	h.start.Do(func() {
+		SubscribeAllTo(h.gsi, h.broadcast)
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.clients[c] = struct{}{}
+	if seq == 0 {
+		return nil, true
+	}
+	return h.history.after(seq)
+}
+
+// unsubscribe removes a client and marks it closed
+func (h *hub) unsubscribe(c *streamClient) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+	c.close()
+}
+
+// closeAll disconnects every client, letting long-lived stream handlers
+// return so that a server shutdown can complete
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.clients {
+		delete(h.clients, c)
+		c.close()
+	}
+}
+
+// broadcast queues an event for every client. Clients whose queue is full
+// are disconnected rather than stalling the publisher.
+func (h *hub) broadcast(event GameEvent) {
+	if name := event.EventName(); name == string(models.Raw) || name == string(models.RawMIRV) {
+		return
+	}
+
+	msg := newStreamMessage(event)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.history.push(msg)
+	for c := range h.clients {
+		select {
+		case c.send <- msg:
+		default:
+			h.gsi.logger.Warn("dropping slow stream client", "event", event.EventName())
+			delete(h.clients, c)
+			c.close()
+		}
+	}
+}
+
+// snapshotMessage builds the message sent to clients when they connect, or
+// nil when no state has been received yet
+func (h *hub) snapshotMessage() *streamMessage {
+	h.gsi.stateMu.RLock()
+	state := h.gsi.current
+	h.gsi.stateMu.RUnlock()
+
+	if state == nil {
+		return nil
+	}
+
+	event := Event[*models.State]{Name: string(models.Data), Data: state}
+	if state.Provider != nil {
+		event.ProviderTimestamp = state.Provider.Timestamp
+	}
+	if state.Map != nil {
+		event.Map = state.Map.Name
+	}
+	event.Round = currentRound(state)
+
+	return newStreamMessage(event)
+}
+
+func newStreamMessage(event GameEvent) *streamMessage {
+	return &streamMessage{event: event, name: event.EventName(), seq: event.sequence()}
+}
+
+// messageRing is a fixed-size buffer of the most recent stream messages
+type messageRing struct {
+	buf   []*streamMessage
+	start int
+	count int
+}
+
+func newMessageRing(size int) *messageRing {
+	return &messageRing{buf: make([]*streamMessage, size)}
+}
+
+func (r *messageRing) push(msg *streamMessage) {
+	end := (r.start + r.count) % len(r.buf)
+	r.buf[end] = msg
+	if r.count < len(r.buf) {
+		r.count++
+		return
+	}
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// after returns the buffered messages with a sequence number above seq, and
+// whether the buffer still covers everything published after seq
+func (r *messageRing) after(seq uint64) ([]*streamMessage, bool) {
+	// Once the ring has wrapped, messages older than the first buffered one
+	// are gone and may have followed seq
+	complete := r.count < len(r.buf) || r.buf[r.start].seq <= seq
+
+	var out []*streamMessage
+	for i := 0; i < r.count; i++ {
+		msg := r.buf[(r.start+i)%len(r.buf)]
+		if msg.seq > seq {
+			out = append(out, msg)
+		}
+	}
+	return out, complete
+}
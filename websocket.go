@@ -0,0 +1,282 @@
+package cs2gsi
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket protocol constants (RFC 6455)
+const (
+	wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+
+	wsMaxClientFrame = 64 * 1024
+	wsPingInterval   = 30 * time.Second
+	wsReadTimeout    = 2 * wsPingInterval
+	wsWriteTimeout   = 10 * time.Second
+)
+
+var errWSProtocol = errors.New("websocket protocol error")
+
+// WebSocketHandler returns an endpoint that upgrades to a WebSocket and
+// pushes the current state on connect followed by every published event
+// (except Raw and RawMIRV) as a JSON text message. With ?format=csgogsi the
+// event names and payloads follow csgogsi instead. Listen mounts it on GET /ws.
+func (gsi *CS2GSI) WebSocketHandler() http.Handler {
+	return http.HandlerFunc(gsi.handleWebSocket)
+}
+
+// handleWebSocket upgrades the connection and streams events until the
+// client disconnects or falls too far behind
+func (gsi *CS2GSI) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	format, err := parseStreamFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := wsUpgrade(w, r)
+	if err != nil {
+		gsi.logger.Warn("websocket upgrade failed", "error", err, "remote_addr", r.RemoteAddr)
+		return
+	}
+	defer conn.Close()
+
+	client := newStreamClient(format)
+	gsi.hub.subscribe(client)
+	defer gsi.hub.unsubscribe(client)
+
+	gsi.logger.Debug("websocket client connected", "remote_addr", r.RemoteAddr)
+
+	go func() {
+		if err := conn.readLoop(); err != nil && !errors.Is(err, io.EOF) {
+			gsi.logger.Debug("websocket read failed", "error", err, "remote_addr", r.RemoteAddr)
+		}
+		client.close()
+	}()
+
+	if snapshot := gsi.hub.snapshotMessage(); snapshot != nil {
+		if err := gsi.writeWebSocketMessage(conn, snapshot, format); err != nil {
+			return
+		}
+	}
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg := <-client.send:
+			if err := gsi.writeWebSocketMessage(conn, msg, format); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.writeFrame(wsOpPing, nil); err != nil {
+				return
+			}
+		case <-client.closed:
+			conn.writeClose()
+			gsi.logger.Debug("websocket client disconnected", "remote_addr", r.RemoteAddr)
+			return
+		}
+	}
+}
+
+// writeWebSocketMessage sends a message as a text frame. Events that fail
+// to encode are skipped.
+func (gsi *CS2GSI) writeWebSocketMessage(conn *wsConn, msg *streamMessage, format streamFormat) error {
+	_, data, err := msg.encode(format)
+	if err != nil {
+		gsi.logger.Error("failed to encode stream event", "event", msg.name, "error", err)
+		return nil
+	}
+	return conn.writeFrame(wsOpText, data)
+}
+
+// wsConn is a server side WebSocket connection
+type wsConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// wsUpgrade performs the opening handshake and hijacks the connection
+func wsUpgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("%w: method %s", errWSProtocol, r.Method)
+	}
+	if !headerContainsToken(r.Header, "Connection", "upgrade") || !headerContainsToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Expected WebSocket upgrade", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: missing upgrade headers", errWSProtocol)
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("%w: unsupported version", errWSProtocol)
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "Missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: missing key", errWSProtocol)
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("failed to hijack connection: %w", err)
+	}
+
+	// The server's read/write timeouts do not apply to long-lived streams
+	conn.SetDeadline(time.Time{})
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + wsAcceptKey(key) + "\r\n\r\n"
+	if _, err := rw.WriteString(response); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, reader: rw.Reader}, nil
+}
+
+func wsAcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeFrame writes a single unfragmented, unmasked frame
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := c.conn.Write(header); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(payload)
+	return err
+}
+
+// writeClose sends a normal closure frame
+func (c *wsConn) writeClose() error {
+	return c.writeFrame(wsOpClose, []byte{0x03, 0xE8})
+}
+
+// readLoop consumes client frames, answering pings and close requests.
+// Data frames from the client are ignored.
+func (c *wsConn) readLoop() error {
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return err
+		}
+
+		switch opcode {
+		case wsOpClose:
+			c.writeFrame(wsOpClose, payload)
+			return io.EOF
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readFrame reads one masked client frame
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return 0, nil, err
+	}
+
+	opcode := head[0] & 0x0F
+	if head[1]&0x80 == 0 {
+		return 0, nil, fmt.Errorf("%w: unmasked client frame", errWSProtocol)
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > wsMaxClientFrame {
+		return 0, nil, fmt.Errorf("%w: frame of %d bytes too large", errWSProtocol, length)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return opcode, payload, nil
+}
+
+// Close closes the underlying connection
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,157 @@
+package cs2gsi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// wsTestClient is a minimal WebSocket client for exercising the server
+type wsTestClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, url string) *wsTestClient {
+	t.Helper()
+
+	addr := strings.TrimPrefix(url, "http://")
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	req := "GET /ws HTTP/1.1\r\nHost: " + addr + "\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatalf("write handshake: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d, want 101", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("accept key = %q", got)
+	}
+
+	return &wsTestClient{conn: conn, reader: reader}
+}
+
+func (c *wsTestClient) readMessage(t *testing.T) map[string]json.RawMessage {
+	t.Helper()
+
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var head [2]byte
+		if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+		length := uint64(head[1] & 0x7F)
+		switch length {
+		case 126:
+			var ext [2]byte
+			io.ReadFull(c.reader, ext[:])
+			length = uint64(binary.BigEndian.Uint16(ext[:]))
+		case 127:
+			var ext [8]byte
+			io.ReadFull(c.reader, ext[:])
+			length = binary.BigEndian.Uint64(ext[:])
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.reader, payload); err != nil {
+			t.Fatalf("read payload: %v", err)
+		}
+		if head[0]&0x0F != wsOpText {
+			continue
+		}
+
+		var msg map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			t.Fatalf("decode message: %v", err)
+		}
+		return msg
+	}
+}
+
+func (c *wsTestClient) close() {
+	// Masked close frame with an all-zero mask
+	c.conn.Write([]byte{0x88, 0x80, 0, 0, 0, 0})
+}
+
+func TestWebSocketStream(t *testing.T) {
+	raw, err := os.ReadFile("testdata/gsi/with_deltas.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	gsi := New(Config{Bus: NewBus()})
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+
+	srv := httptest.NewServer(gsi.WebSocketHandler())
+	defer srv.Close()
+
+	client := dialWebSocket(t, srv.URL)
+
+	snapshot := client.readMessage(t)
+	if string(snapshot["name"]) != `"data"` {
+		t.Fatalf("first message name = %s, want data snapshot", snapshot["name"])
+	}
+
+	// The hub subscribes before the snapshot is sent, so the next Digest is
+	// delivered to the client
+	if err := gsi.Digest(raw); err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	event := client.readMessage(t)
+	if string(event["name"]) != `"data"` {
+		t.Fatalf("event name = %s, want data", event["name"])
+	}
+	if string(event["map"]) != `"de_dust2"` {
+		t.Fatalf("event map = %s, want de_dust2", event["map"])
+	}
+
+	client.close()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		gsi.hub.mu.Lock()
+		n := len(gsi.hub.clients)
+		gsi.hub.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("client was not removed after disconnect")
+}
+
+func TestWebSocketRejectsPlainRequest(t *testing.T) {
+	gsi := New(Config{Bus: NewBus()})
+	srv := httptest.NewServer(gsi.WebSocketHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+}